@@ -35,6 +35,146 @@ func TestPreAndPostBuild(t *testing.T) {
 	RunBuildTest("pre-and-post-build", t, false)
 }
 
+func TestShortcodes(t *testing.T) {
+	RunBuildTest("shortcodes", t, false)
+}
+
+func TestPageBundles(t *testing.T) {
+	RunBuildTest("page-bundles", t, false)
+}
+
+func TestTableOfContents(t *testing.T) {
+	RunBuildTest("table-of-contents", t, false)
+}
+
+func TestCustomPaths(t *testing.T) {
+	RunBuildTest("custom-paths", t, false)
+}
+
+func TestDatedFilenames(t *testing.T) {
+	RunBuildTest("dated-filenames", t, false)
+}
+
+func TestScheduledPosts(t *testing.T) {
+	RunBuildTest("scheduled-posts", t, false)
+}
+
+func TestScheduledPostsWithFuture(t *testing.T) {
+	t.Parallel()
+	now, err := time.Parse(time.RFC3339, "2024-03-01T10:00:00Z")
+	assert.NoError(t, err)
+
+	publicDir, err := buildFixture(t, "scheduled-posts", false, true, now)
+	assert.NoError(t, err)
+
+	assert.FileExists(t, path.Join(publicDir, "posts", "queued", "index.html"))
+	assert.NoFileExists(t, path.Join(publicDir, "posts", "sale", "index.html"))
+}
+
+func TestUpdatedPages(t *testing.T) {
+	RunBuildTest("updated-pages", t, false)
+}
+
+func TestSectionSorting(t *testing.T) {
+	RunBuildTest("section-sorting", t, false)
+}
+
+func TestNestedSections(t *testing.T) {
+	RunBuildTest("nested-sections", t, false)
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	RunBuildTest("breadcrumbs", t, false)
+}
+
+func TestMenus(t *testing.T) {
+	RunBuildTest("menus", t, false)
+}
+
+func TestMultilingual(t *testing.T) {
+	RunBuildTest("multilingual", t, false)
+}
+
+func TestTranslatedStrings(t *testing.T) {
+	RunBuildTest("i18n", t, false)
+}
+
+func TestDataFiles(t *testing.T) {
+	RunBuildTest("data-files", t, false)
+}
+
+func TestTaxonomyFeeds(t *testing.T) {
+	RunBuildTest("taxonomy-feeds", t, false)
+}
+
+func TestTermPages(t *testing.T) {
+	RunBuildTest("term-pages", t, false)
+}
+
+func TestTaxonomyTerms(t *testing.T) {
+	RunBuildTest("taxonomy-terms", t, false)
+}
+
+func TestHierarchicalTaxonomies(t *testing.T) {
+	RunBuildTest("hierarchical-taxonomies", t, false)
+}
+
+func TestConfigTaxonomies(t *testing.T) {
+	RunBuildTest("config-taxonomies", t, false)
+}
+
+func TestTaxonomiesWithoutTemplates(t *testing.T) {
+	RunBuildTest("taxonomies-without-templates", t, false)
+}
+
+func TestTaxonomyTermCollisions(t *testing.T) {
+	t.Parallel()
+	_, err := buildFixture(t, "taxonomy-term-collisions", false, false, time.Now())
+	assert.EqualError(
+		t,
+		err,
+		"coffee.md: the terms \"Food\" and \"food\" of categories have the same slug \"food\"\n"+
+			"coffee.md: the term \"?!\" of categories has no letters or digits for its URL\n"+
+			"coffee.md: the terms \"Cafe\" and \"Café\" of tags have the same slug \"cafe\"",
+	)
+}
+
+func TestAliasCollisions(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+
+	_, err := buildFixture(t, "alias-term-collision", false, false, now)
+	assert.ErrorContains(t, err, "the alias \"/tags/food/\" of coffee.md")
+	assert.ErrorContains(t, err, "the page at /tags/food/")
+	assert.ErrorContains(t, err, "are both written to tags/food/index.html")
+
+	_, err = buildFixture(t, "alias-pagination-collision", false, false, now)
+	assert.ErrorContains(t, err, "the alias \"/posts/page/2/\" of about.md")
+	assert.ErrorContains(t, err, "the page at /posts/page/2/")
+	assert.ErrorContains(t, err, "are both written to posts/page/2/index.html")
+
+	_, err = buildFixture(t, "alias-static-collision", false, false, now)
+	assert.ErrorContains(t, err, "the alias \"/about.html\" of about.md and the static file about.html")
+	assert.ErrorContains(t, err, "are both written to about.html")
+}
+
+func TestInternalLinks(t *testing.T) {
+	RunBuildTest("internal-links", t, false)
+}
+
+func TestBrokenInternalLinks(t *testing.T) {
+	t.Parallel()
+	_, err := buildFixture(t, "broken-internal-links", false, false, time.Now())
+	assert.EqualError(
+		t,
+		err,
+		"index.md: link to missing page \"missing\"\nindex.md: link to draft page \"plans.md\"",
+	)
+
+	_, err = buildFixture(t, "broken-internal-links", true, false, time.Now())
+	assert.EqualError(t, err, "index.md: link to missing page \"missing\"")
+}
+
 func TestFeeds(t *testing.T) {
 	RunBuildTest("feeds", t, false)
 }
@@ -54,8 +194,22 @@ func RunBuildTest(fixture string, t *testing.T, verbose bool) {
 	now, err := time.Parse(time.RFC3339, "2024-03-01T10:00:00Z")
 	assert.NoError(t, err)
 
-	err = commands.Build(siteDir, publicDir, false, verbose, now)
+	err = commands.Build(siteDir, publicDir, false, false, verbose, now)
 	assert.NoError(t, err)
 
 	assertDirContents(t, expectedDir, publicDir)
 }
+
+// buildFixture builds the fixture site into a temporary directory that is
+// removed when the test ends, and returns that directory.
+func buildFixture(t *testing.T, fixture string, drafts, future bool, now time.Time) (publicDir string, err error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	publicDir = t.TempDir()
+	err = commands.Build(path.Join(cwd, "fixtures", fixture), publicDir, drafts, future, false, now)
+
+	return publicDir, err
+}
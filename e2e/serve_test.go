@@ -38,7 +38,7 @@ func TestServer(t *testing.T) {
 	fixturesDirectory := path.Join(cwd, "fixtures")
 
 	// Start the test server
-	srv, err := server.NewServer(path.Join(fixturesDirectory, "blog-posts"), false, false)
+	srv, err := server.NewServer(path.Join(fixturesDirectory, "blog-posts"), false, false, false)
 	if err != nil {
 		t.Fatal(err)
 	}
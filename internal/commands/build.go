@@ -8,7 +8,7 @@ import (
 	"codeberg.org/asartalo/assg/internal/generator"
 )
 
-func Build(srcDir, outputDir string, includeDrafts bool, verbose bool, now time.Time) error {
+func Build(srcDir, outputDir string, includeDrafts, includeFuture, verbose bool, now time.Time) error {
 	config, err := config.Load(path.Join(srcDir, "config.toml"))
 	if err != nil {
 		return err
@@ -18,6 +18,9 @@ func Build(srcDir, outputDir string, includeDrafts bool, verbose bool, now time.
 		config.OutputDirectory = outputDir
 	}
 	config.IncludeDrafts = includeDrafts
+	if includeFuture {
+		config.IncludeFuture = true
+	}
 	gen, err := generator.New(config, verbose)
 
 	if err != nil {
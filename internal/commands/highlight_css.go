@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"path"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"codeberg.org/asartalo/assg/internal/markdown"
+)
+
+func HighlightCSS(srcDir, style, outputFile string) error {
+	config, err := config.Load(path.Join(srcDir, "config.toml"))
+	if err != nil {
+		return err
+	}
+
+	if style != "" {
+		config.Markdown.HighlightStyle = style
+	}
+
+	var output io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.OpenFile(outputFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		output = file
+	}
+
+	return markdown.WriteHighlightCSS(output, config.Markdown)
+}
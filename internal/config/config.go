@@ -1,8 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -59,24 +63,30 @@ func (cf ContentFeed) IncludeAllInitially() bool {
 }
 
 type Config struct {
-	BaseURL          string           `toml:"base_url"`
-	Title            string           `toml:"title"`
-	Description      string           `toml:"description"`
-	DefaultLanguage  string           `toml:"default_language"`
-	Author           string           `toml:"author"`
-	CompileSass      bool             `toml:"compile_sass"`
-	GenerateFeed     bool             `toml:"generate_feed"`
-	FeedLimit        int              `toml:"feed_limit"`
-	FeedsForContent  []ContentFeed    `toml:"feeds_for_content"`
-	Taxonomies       []TaxonomyConfig `toml:"taxonomies"`
-	Markdown         MarkdownConfig   `toml:"markdown"`
-	ContentDirectory string           `toml:"content_directory"`
-	OutputDirectory  string           `toml:"output_directory"`
-	IncludeDrafts    bool             `toml:"include_drafts"`
-	Sitemap          bool             `toml:"sitemap"`
-	PreBuildCmd      string           `toml:"prebuild"`
-	PostBuildCmd     string           `toml:"postbuild"`
-	ServerConfig     ServerConfig     `toml:"server"`
+	BaseURL          string                    `toml:"base_url"`
+	Title            string                    `toml:"title"`
+	Description      string                    `toml:"description"`
+	DefaultLanguage  string                    `toml:"default_language"`
+	Languages        map[string]LanguageConfig `toml:"languages"`
+	Author           string                    `toml:"author"`
+	CompileSass      bool                      `toml:"compile_sass"`
+	GenerateFeed     bool                      `toml:"generate_feed"`
+	FeedLimit        int                       `toml:"feed_limit"`
+	FeedsForContent  []ContentFeed             `toml:"feeds_for_content"`
+	SummaryLength    int                       `toml:"summary_length"`
+	Taxonomies       TaxonomyConfigs           `toml:"taxonomies"`
+	Menus            map[string][]MenuEntry    `toml:"menus"`
+	Markdown         MarkdownConfig            `toml:"markdown"`
+	Filenames        FilenameConfig            `toml:"filenames"`
+	ContentDirectory string                    `toml:"content_directory"`
+	OutputDirectory  string                    `toml:"output_directory"`
+	IncludeDrafts    bool                      `toml:"include_drafts"`
+	IncludeFuture    bool                      `toml:"include_future"`
+	UpdatedFromGit   bool                      `toml:"updated_from_git"`
+	Sitemap          bool                      `toml:"sitemap"`
+	PreBuildCmd      string                    `toml:"prebuild"`
+	PostBuildCmd     string                    `toml:"postbuild"`
+	ServerConfig     ServerConfig              `toml:"server"`
 	DevMode          bool
 	rootDirectory    string
 }
@@ -90,11 +100,208 @@ type TaxonomyConfig struct {
 	Name       string `toml:"name"`
 	Feed       bool   `toml:"feed"`
 	PaginateBy int    `toml:"paginate_by"` // Add this field for optional pagination
+	// CaseSensitive keeps terms that differ only in case apart, which is an
+	// error as they have the same slug. Otherwise they are the same term.
+	CaseSensitive bool `toml:"case_sensitive"`
+	// Hierarchical taxonomies have terms written as paths, like "tech/go",
+	// which are under the terms before them
+	Hierarchical bool `toml:"hierarchical"`
+	// Aliases maps terms to the term they are replaced with
+	Aliases map[string]string `toml:"aliases"`
+}
+
+// TaxonomyConfigs are the taxonomies of the site. They are declared as an
+// array, `taxonomies = [{ name = "tags" }]`, or as tables named after each
+// taxonomy, `[taxonomies.tags]`.
+type TaxonomyConfigs []TaxonomyConfig
+
+func (t *TaxonomyConfigs) UnmarshalTOML(data any) error {
+	switch value := data.(type) {
+	case []map[string]any:
+		for _, table := range value {
+			taxonomy, err := decodeTaxonomy(table)
+			if err != nil {
+				return err
+			}
+			*t = append(*t, taxonomy)
+		}
+	case []any:
+		for _, item := range value {
+			table, ok := item.(map[string]any)
+			if !ok {
+				return fmt.Errorf("taxonomies must be tables")
+			}
+
+			taxonomy, err := decodeTaxonomy(table)
+			if err != nil {
+				return err
+			}
+			*t = append(*t, taxonomy)
+		}
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			table, ok := value[name].(map[string]any)
+			if !ok {
+				return fmt.Errorf("taxonomies.%s is not a table", name)
+			}
+
+			taxonomy, err := decodeTaxonomy(table)
+			if err != nil {
+				return err
+			}
+			taxonomy.Name = name
+			*t = append(*t, taxonomy)
+		}
+	default:
+		return fmt.Errorf("taxonomies must be an array or a table")
+	}
+
+	return nil
+}
+
+// Find returns the configuration of the taxonomy with the name.
+func (t TaxonomyConfigs) Find(name string) (TaxonomyConfig, bool) {
+	for _, taxonomy := range t {
+		if taxonomy.Name == name {
+			return taxonomy, true
+		}
+	}
+
+	return TaxonomyConfig{}, false
+}
+
+func decodeTaxonomy(table map[string]any) (TaxonomyConfig, error) {
+	taxonomy := TaxonomyConfig{}
+	encoded, err := toml.Marshal(table)
+	if err != nil {
+		return taxonomy, err
+	}
+
+	err = toml.Unmarshal(encoded, &taxonomy)
+
+	return taxonomy, err
+}
+
+// LanguageConfig is a language the site is translated to, declared with
+// [languages.<code>].
+type LanguageConfig struct {
+	// Title and Description replace the site's in the language's feeds
+	Title       string `toml:"title"`
+	Description string `toml:"description"`
+}
+
+// MenuEntry is a link in a menu declared with [[menus.<name>]].
+type MenuEntry struct {
+	Name     string      `toml:"name"`
+	Url      string      `toml:"url"`
+	Weight   int         `toml:"weight"`
+	Children []MenuEntry `toml:"children"`
 }
 
 type MarkdownConfig struct {
-	HighlightCode    bool `toml:"highlight_code"`
-	SmartPunctuation bool `toml:"smart_punctuation"`
+	HighlightCode    bool   `toml:"highlight_code"`
+	HighlightStyle   string `toml:"highlight_style"`
+	HighlightClasses bool   `toml:"highlight_classes"`
+	SmartPunctuation bool   `toml:"smart_punctuation"`
+	Tables           bool   `toml:"tables"`
+	Strikethrough    bool   `toml:"strikethrough"`
+	Footnotes        bool   `toml:"footnotes"`
+	Figures          bool   `toml:"figures"`
+	DefinitionLists  bool   `toml:"definition_lists"`
+	TaskLists        bool   `toml:"task_lists"`
+	Linkify          bool   `toml:"linkify"`
+	CJK              bool   `toml:"cjk"`
+	RawHTML          bool   `toml:"raw_html"`
+	TocMinLevel      int    `toml:"toc_min_level"`
+	TocMaxLevel      int    `toml:"toc_max_level"`
+	// Punctuation overrides the typographic substitutions by language
+	Punctuation map[string]PunctuationConfig `toml:"punctuation"`
+}
+
+type PunctuationConfig struct {
+	LeftSingleQuote  string `toml:"left_single_quote"`
+	RightSingleQuote string `toml:"right_single_quote"`
+	LeftDoubleQuote  string `toml:"left_double_quote"`
+	RightDoubleQuote string `toml:"right_double_quote"`
+	EnDash           string `toml:"en_dash"`
+	EmDash           string `toml:"em_dash"`
+	Ellipsis         string `toml:"ellipsis"`
+	LeftAngleQuote   string `toml:"left_angle_quote"`
+	RightAngleQuote  string `toml:"right_angle_quote"`
+	Apostrophe       string `toml:"apostrophe"`
+}
+
+// FilenameConfig sets how dates and slugs are taken from filenames like
+// 2024-02-01-day-1.md.
+type FilenameConfig struct {
+	// DatePattern matches the filename without its extension. Its "date" group
+	// is the page's date and its "slug" group the page's slug.
+	DatePattern string `toml:"date_pattern"`
+	// DateLayout is the Go time layout of the "date" group
+	DateLayout string `toml:"date_layout"`
+	// ExtractSlug uses the "slug" group as the page's slug. It is off by
+	// default so that dated files keep their URLs.
+	ExtractSlug bool `toml:"extract_slug"`
+	// FrontMatterWins keeps the date and slug set in the front matter
+	FrontMatterWins bool `toml:"front_matter_wins"`
+}
+
+const DefaultFilenameDatePattern = `^(?P<date>\d{4}-\d{2}-\d{2})-(?P<slug>.+)$`
+
+// DefaultFilenameConfig returns the settings used for keys that are missing
+// from the [filenames] section.
+func DefaultFilenameConfig() FilenameConfig {
+	return FilenameConfig{
+		DatePattern:     DefaultFilenameDatePattern,
+		DateLayout:      time.DateOnly,
+		FrontMatterWins: true,
+	}
+}
+
+const DefaultHighlightStyle = "github"
+
+// DefaultMarkdownConfig returns the Markdown settings used for keys that are
+// missing from the [markdown] section.
+func DefaultMarkdownConfig() MarkdownConfig {
+	return MarkdownConfig{
+		HighlightStyle:   DefaultHighlightStyle,
+		SmartPunctuation: true,
+		Tables:           true,
+		Strikethrough:    true,
+		Footnotes:        true,
+		Figures:          true,
+		RawHTML:          true,
+		TocMinLevel:      1,
+		TocMaxLevel:      6,
+	}
+}
+
+// SiteLanguage returns the default language of the site, "en" if not set.
+func (c *Config) SiteLanguage() string {
+	if c.DefaultLanguage == "" {
+		return "en"
+	}
+
+	return c.DefaultLanguage
+}
+
+// TranslationLanguages returns the sorted codes of the languages other than the
+// default language.
+func (c *Config) TranslationLanguages() []string {
+	languages := []string{}
+	for code := range c.Languages {
+		if code != c.SiteLanguage() {
+			languages = append(languages, code)
+		}
+	}
+	slices.Sort(languages)
+
+	return languages
+}
+
+// FindTaxonomy returns the configuration of the taxonomy with the name.
+func (c *Config) FindTaxonomy(name string) (TaxonomyConfig, bool) {
+	return c.Taxonomies.Find(name)
 }
 
 func (c *Config) RootDirectory() string {
@@ -118,7 +325,10 @@ func (c *Config) OutputDirectoryAbsolute() string {
 }
 
 func Load(filename string) (*Config, error) {
-	var config Config
+	config := Config{
+		Markdown:  DefaultMarkdownConfig(),
+		Filenames: DefaultFilenameConfig(),
+	}
 	_, err := toml.DecodeFile(filename, &config)
 	if err != nil {
 		return nil, err
@@ -127,9 +337,36 @@ func Load(filename string) (*Config, error) {
 	config.rootDirectory = filepath.Dir(filename)
 	setDefaults(&config)
 
+	err = validateTocLevels(config.Markdown)
+	if err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// validateTocLevels checks that the table of contents levels are heading
+// levels and in order.
+func validateTocLevels(markdown MarkdownConfig) error {
+	if markdown.TocMinLevel < 1 || markdown.TocMinLevel > 6 {
+		return fmt.Errorf("markdown.toc_min_level must be between 1 and 6, not %d", markdown.TocMinLevel)
+	}
+
+	if markdown.TocMaxLevel < 1 || markdown.TocMaxLevel > 6 {
+		return fmt.Errorf("markdown.toc_max_level must be between 1 and 6, not %d", markdown.TocMaxLevel)
+	}
+
+	if markdown.TocMinLevel > markdown.TocMaxLevel {
+		return fmt.Errorf(
+			"markdown.toc_min_level (%d) is greater than markdown.toc_max_level (%d)",
+			markdown.TocMinLevel,
+			markdown.TocMaxLevel,
+		)
+	}
+
+	return nil
+}
+
 func setDefaults(config *Config) {
 	if config.ContentDirectory == "" {
 		config.ContentDirectory = "content"
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadConfig(t *testing.T, toml string) (*Config, error) {
+	filename := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(filename, []byte(toml), 0644))
+
+	return Load(filename)
+}
+
+func TestTocLevels(t *testing.T) {
+	config, err := loadConfig(t, "[markdown]\ntoc_min_level = 2\ntoc_max_level = 3\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, config.Markdown.TocMinLevel)
+	assert.Equal(t, 3, config.Markdown.TocMaxLevel)
+
+	tests := []struct {
+		toml string
+		err  string
+	}{
+		{
+			toml: "[markdown]\ntoc_min_level = 0\n",
+			err:  "markdown.toc_min_level must be between 1 and 6, not 0",
+		},
+		{
+			toml: "[markdown]\ntoc_max_level = 7\n",
+			err:  "markdown.toc_max_level must be between 1 and 6, not 7",
+		},
+		{
+			toml: "[markdown]\ntoc_min_level = 4\ntoc_max_level = 2\n",
+			err:  "markdown.toc_min_level (4) is greater than markdown.toc_max_level (2)",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := loadConfig(t, test.toml)
+		assert.EqualError(t, err, test.err)
+	}
+}
@@ -0,0 +1,166 @@
+package content
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadData reads the TOML, JSON, YAML and CSV files in dir into a map keyed by
+// the names of the files without their extensions. Subdirectories are nested
+// maps. A CSV file is a list of rows keyed by the column names in its first
+// row. A missing directory has no data.
+func LoadData(dir string) (map[string]any, error) {
+	data := make(map[string]any)
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && filePath == dir {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+
+		if entry.Name()[0] == '.' {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() || !isDataFile(filePath) {
+			return nil
+		}
+
+		value, err := decodeDataFile(filePath)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", filePath, err)
+		}
+
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		return setData(data, files, relPath, value)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func isDataFile(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".toml", ".json", ".yaml", ".yml", ".csv":
+		return true
+	}
+
+	return false
+}
+
+// setData puts the value of the file at relPath in the nested map. files maps
+// the keys taken by files to their paths.
+func setData(data map[string]any, files map[string]string, relPath string, value any) error {
+	relPath = filepath.ToSlash(relPath)
+	key := strings.TrimSuffix(relPath, path.Ext(relPath))
+	parts := strings.Split(key, "/")
+
+	current := data
+	for i, part := range parts[:len(parts)-1] {
+		dirKey := strings.Join(parts[:i+1], "/")
+		if file, ok := files[dirKey]; ok {
+			return fmt.Errorf("%s and the directory %s are both \"%s\"", file, dirKey, dirKey)
+		}
+
+		child, ok := current[part].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			current[part] = child
+		}
+		current = child
+	}
+
+	name := parts[len(parts)-1]
+	if _, exists := current[name]; exists {
+		existing, ok := files[key]
+		if !ok {
+			existing = "the directory " + key
+		}
+
+		return fmt.Errorf("%s and %s are both \"%s\"", existing, relPath, key)
+	}
+
+	current[name] = value
+	files[key] = relPath
+
+	return nil
+}
+
+func decodeDataFile(filePath string) (any, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".toml":
+		table := make(map[string]any)
+		_, err = toml.Decode(string(content), &table)
+		value = table
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&value)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &value)
+	case ".csv":
+		value, err = decodeCSV(content)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeValue(value), nil
+}
+
+// decodeCSV returns the rows after the first, keyed by the first row.
+func decodeCSV(content []byte) ([]any, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []any{}
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]any)
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,70 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeDataFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+	}
+
+	return dir
+}
+
+func TestLoadData(t *testing.T) {
+	dir := writeDataFiles(t, map[string]string{
+		"site.toml":          "owner = \"Jane\"\nyear = 2024\n",
+		"talks.json":         `[{"title": "Go Templates", "minutes": 30}]`,
+		"team/authors.yaml":  "jane:\n  name: Jane Doe\n  posts: 12\n",
+		"projects.csv":       "name,url\nassg,https://codeberg.org/asartalo/assg\n",
+		"notes.txt":          "not data",
+		".hidden/secret.yml": "secret: true\n",
+	})
+
+	data, err := LoadData(dir)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"site":  map[string]any{"owner": "Jane", "year": int64(2024)},
+		"talks": []any{map[string]any{"title": "Go Templates", "minutes": int64(30)}},
+		"team": map[string]any{
+			"authors": map[string]any{
+				"jane": map[string]any{"name": "Jane Doe", "posts": int64(12)},
+			},
+		},
+		"projects": []any{
+			map[string]any{"name": "assg", "url": "https://codeberg.org/asartalo/assg"},
+		},
+	}, data)
+}
+
+func TestLoadDataWithoutDirectory(t *testing.T) {
+	data, err := LoadData(filepath.Join(t.TempDir(), "data"))
+
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestLoadDataConflicts(t *testing.T) {
+	dir := writeDataFiles(t, map[string]string{
+		"talks.json": `[]`,
+		"talks.toml": "",
+	})
+	_, err := LoadData(dir)
+	assert.ErrorContains(t, err, `talks.json and talks.toml are both "talks"`)
+
+	dir = writeDataFiles(t, map[string]string{
+		"team.toml":         "",
+		"team/authors.toml": "",
+	})
+	_, err = LoadData(dir)
+	assert.ErrorContains(t, err, `the directory team and team.toml are both "team"`)
+}
@@ -0,0 +1,74 @@
+package content
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"codeberg.org/asartalo/assg/internal/config"
+)
+
+// FilenameDates takes the date and slug of a page from a dated filename like
+// 2024-02-01-day-1.md (see config.FilenameConfig).
+type FilenameDates struct {
+	pattern         *regexp.Regexp
+	layout          string
+	extractSlug     bool
+	frontMatterWins bool
+}
+
+// NewFilenameDates returns nil when there is no date pattern.
+func NewFilenameDates(cfg config.FilenameConfig) (*FilenameDates, error) {
+	if cfg.DatePattern == "" {
+		return nil, nil
+	}
+
+	pattern, err := regexp.Compile(cfg.DatePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filenames.date_pattern: %w", err)
+	}
+
+	if pattern.SubexpIndex("date") < 0 {
+		return nil, fmt.Errorf("filenames.date_pattern has no \"date\" group")
+	}
+
+	return &FilenameDates{
+		pattern:         pattern,
+		layout:          cfg.DateLayout,
+		extractSlug:     cfg.ExtractSlug,
+		frontMatterWins: cfg.FrontMatterWins,
+	}, nil
+}
+
+// Apply sets the page's date, and its slug with extract_slug, from its filename,
+// or from its directory for page bundles.
+func (fd *FilenameDates) Apply(page *WebPage) error {
+	sourcePath := page.SourcePath()
+	if sourcePath == "" {
+		return nil
+	}
+
+	match := fd.pattern.FindStringSubmatch(filepath.Base(sourcePath))
+	if match == nil {
+		return nil
+	}
+
+	date, err := time.Parse(fd.layout, match[fd.pattern.SubexpIndex("date")])
+	if err != nil {
+		return fmt.Errorf("unable to parse the date in the filename of %s: %w", page.MarkdownPath, err)
+	}
+
+	fm := &page.FrontMatter
+	if !fd.frontMatterWins || fm.Date.IsZero() {
+		fm.Date = date
+	}
+
+	if i := fd.pattern.SubexpIndex("slug"); fd.extractSlug && i >= 0 && match[i] != "" {
+		if !fd.frontMatterWins || fm.Slug == "" {
+			fm.Slug = match[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilenameDates(t *testing.T) {
+	fd, err := NewFilenameDates(config.DefaultFilenameConfig())
+	assert.NoError(t, err)
+
+	unslugged := NewPage(testMarkdown, "posts/2024-02-01-day-1.md", FrontMatter{})
+	assert.NoError(t, fd.Apply(unslugged))
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), unslugged.FrontMatter.Date)
+	assert.Equal(t, "", unslugged.FrontMatter.Slug)
+	assert.Equal(t, "posts/2024-02-01-day-1", unslugged.RenderedPath())
+
+	cfg := config.DefaultFilenameConfig()
+	cfg.ExtractSlug = true
+	fd, err = NewFilenameDates(cfg)
+	assert.NoError(t, err)
+
+	page := NewPage(testMarkdown, "posts/2024-02-01-day-1.md", FrontMatter{})
+	assert.NoError(t, fd.Apply(page))
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), page.FrontMatter.Date)
+	assert.Equal(t, "day-1", page.FrontMatter.Slug)
+	assert.Equal(t, "posts/day-1", page.RenderedPath())
+
+	bundle := NewPage(testMarkdown, "posts/2024-02-02-day-2/index.md", FrontMatter{})
+	assert.NoError(t, fd.Apply(bundle))
+	assert.Equal(t, "posts/day-2", bundle.RenderedPath())
+
+	undated := NewPage(testMarkdown, "posts/day-3.md", FrontMatter{})
+	assert.NoError(t, fd.Apply(undated))
+	assert.True(t, undated.FrontMatter.Date.IsZero())
+	assert.Equal(t, "", undated.FrontMatter.Slug)
+}
+
+func TestFilenameDatesAndFrontMatter(t *testing.T) {
+	frontMatter := FrontMatter{
+		Date: time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC),
+		Slug: "explicit",
+	}
+
+	fd, err := NewFilenameDates(config.DefaultFilenameConfig())
+	assert.NoError(t, err)
+	page := NewPage(testMarkdown, "2024-02-01-day-1.md", frontMatter)
+	assert.NoError(t, fd.Apply(page))
+	assert.Equal(t, frontMatter.Date, page.FrontMatter.Date)
+	assert.Equal(t, "explicit", page.FrontMatter.Slug)
+
+	cfg := config.DefaultFilenameConfig()
+	cfg.ExtractSlug = true
+	cfg.FrontMatterWins = false
+	fd, err = NewFilenameDates(cfg)
+	assert.NoError(t, err)
+	page = NewPage(testMarkdown, "2024-02-01-day-1.md", frontMatter)
+	assert.NoError(t, fd.Apply(page))
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), page.FrontMatter.Date)
+	assert.Equal(t, "day-1", page.FrontMatter.Slug)
+}
+
+func TestCustomFilenamePattern(t *testing.T) {
+	fd, err := NewFilenameDates(config.FilenameConfig{
+		DatePattern: `^(?P<slug>.+)_(?P<date>\d{8})$`,
+		DateLayout:  "20060102",
+		ExtractSlug: true,
+	})
+	assert.NoError(t, err)
+
+	page := NewPage(testMarkdown, "trip_20240201.md", FrontMatter{})
+	assert.NoError(t, fd.Apply(page))
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), page.FrontMatter.Date)
+	assert.Equal(t, "trip", page.RenderedPath())
+
+	fd, err = NewFilenameDates(config.FilenameConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, fd)
+
+	_, err = NewFilenameDates(config.FilenameConfig{DatePattern: `^(?P<slug>.+)$`})
+	assert.EqualError(t, err, "filenames.date_pattern has no \"date\" group")
+
+	fd, err = NewFilenameDates(config.DefaultFilenameConfig())
+	assert.NoError(t, err)
+	assert.EqualError(
+		t,
+		fd.Apply(NewPage(testMarkdown, "2024-13-01-bad.md", FrontMatter{})),
+		"unable to parse the date in the filename of 2024-13-01-bad.md: "+
+			"parsing time \"2024-13-01\": month out of range",
+	)
+}
@@ -0,0 +1,94 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
+// jsonFrontMatterStart matches content that opens with a JSON object, as
+// opposed to Markdown that happens to start with a brace.
+var jsonFrontMatterStart = regexp.MustCompile(`^\s*\{\s*("|\})`)
+
+// splitJSONFrontMatter separates JSON frontmatter from the Markdown body.
+// If the content has no JSON frontmatter, the returned frontmatter is nil.
+func splitJSONFrontMatter(content []byte) (body []byte, frontMatter []byte, err error) {
+	if !jsonFrontMatterStart.Match(content) {
+		return content, nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+
+	return content[decoder.InputOffset():], raw, nil
+}
+
+// jsonDateFields are the front matter dates that can be written without a
+// time, like 2024-02-01, as TOML and YAML allow.
+var jsonDateFields = []string{"date", "updated", "expiry_date"}
+
+func decodeJSONFrontMatter(data []byte, fm *FrontMatter) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err == nil {
+		changed := false
+		for _, key := range jsonDateFields {
+			var value string
+			if json.Unmarshal(fields[key], &value) != nil {
+				continue
+			}
+
+			if date, err := time.Parse(time.DateOnly, value); err == nil {
+				fields[key], _ = json.Marshal(date)
+				changed = true
+			}
+		}
+
+		if changed {
+			data, _ = json.Marshal(fields)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(fm)
+}
+
+// normalizeExtra converts the numbers decoded from YAML and JSON to the same
+// types that TOML decodes to so that templates see the same values whatever
+// the frontmatter format.
+func normalizeExtra(extra map[string]any) map[string]any {
+	for key, value := range extra {
+		extra[key] = normalizeValue(value)
+	}
+
+	return extra
+}
+
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		return normalizeExtra(v)
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,141 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tomlPage = `+++
+title = "Same Page"
+date = 2024-02-01T10:00:00Z
+updated = 2024-02-03
+expiry_date = 2099-01-01
+description = "Same page in any format"
+language = "en"
+
+[taxonomies]
+tags = ["food", "life"]
+
+[index]
+sort_by = "date"
+page_template = "post.html"
+paginate_by = 5
+
+[extra]
+status = "done"
+count = 3
+ratio = 0.5
+nested = { list = [1, 2], flag = true }
++++
+
+Hello "world".
+`
+
+const yamlPage = `---
+title: Same Page
+date: 2024-02-01T10:00:00Z
+updated: 2024-02-03
+expiry_date: 2099-01-01
+description: Same page in any format
+language: en
+taxonomies:
+  tags: [food, life]
+index:
+  sort_by: date
+  page_template: post.html
+  paginate_by: 5
+extra:
+  status: done
+  count: 3
+  ratio: 0.5
+  nested:
+    list: [1, 2]
+    flag: true
+---
+
+Hello "world".
+`
+
+const jsonPage = `{
+  "title": "Same Page",
+  "date": "2024-02-01T10:00:00Z",
+  "updated": "2024-02-03",
+  "expiry_date": "2099-01-01",
+  "description": "Same page in any format",
+  "language": "en",
+  "taxonomies": { "tags": ["food", "life"] },
+  "index": { "sort_by": "date", "page_template": "post.html", "paginate_by": 5 },
+  "extra": {
+    "status": "done",
+    "count": 3,
+    "ratio": 0.5,
+    "nested": { "list": [1, 2], "flag": true }
+  }
+}
+
+Hello "world".
+`
+
+func TestFrontMatterFormatParity(t *testing.T) {
+	expected := FrontMatter{
+		Title:       "Same Page",
+		Date:        time.Date(2024, time.February, 1, 10, 0, 0, 0, time.UTC),
+		Updated:     time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC),
+		ExpiryDate:  time.Date(2099, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Same page in any format",
+		Language:    "en",
+		Taxonomies:  map[string][]string{"tags": {"food", "life"}},
+		Index: IndexFields{
+			SortBy:       "date",
+			PageTemplate: "post.html",
+			PaginateBy:   5,
+		},
+		Extra: map[string]any{
+			"status": "done",
+			"count":  int64(3),
+			"ratio":  0.5,
+			"nested": map[string]any{
+				"list": []any{int64(1), int64(2)},
+				"flag": true,
+			},
+		},
+	}
+
+	for format, md := range map[string]string{"toml": tomlPage, "yaml": yamlPage, "json": jsonPage} {
+		t.Run(format, func(t *testing.T) {
+			a := assert.New(t)
+			page, err := ParsePage(testMarkdown, "same.md", []byte(md))
+
+			a.NoError(err)
+			a.Equal(expected, page.FrontMatter)
+			a.Equal("<p>Hello “world”.</p>\n", page.Content.String())
+			a.True(page.IsIndex())
+		})
+	}
+}
+
+func TestJSONFrontMatterLanguageIsUsedForPunctuation(t *testing.T) {
+	md := `{ "title": "Salut", "language": "fr" }
+Il a dit "bonjour".
+`
+	page, err := ParsePage(testMarkdown, "salut.md", []byte(md))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Salut", page.FrontMatter.Title)
+	assert.Equal(t, "<p>Il a dit «\u00a0bonjour\u00a0».</p>\n", page.Content.String())
+}
+
+func TestContentStartingWithBraceIsNotFrontMatter(t *testing.T) {
+	page, err := ParsePage(testMarkdown, "brace.md", []byte("{braces} are fine.\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>{braces} are fine.</p>\n", page.Content.String())
+}
+
+func TestInvalidJSONFrontMatter(t *testing.T) {
+	_, err := ParsePage(testMarkdown, "broken.md", []byte("{ \"title\": \"Broken\"\n\nHello.\n"))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations are the replacements for letters that don't decompose into
+// a base letter and accents, and for symbols that are part of a name, like the
+// "+" in "C++".
+var transliterations = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'Æ': "AE",
+	'œ': "oe",
+	'Œ': "OE",
+	'ø': "o",
+	'Ø': "O",
+	'ł': "l",
+	'Ł': "L",
+	'đ': "d",
+	'Đ': "D",
+	'ð': "d",
+	'Ð': "D",
+	'þ': "th",
+	'Þ': "TH",
+	'ı': "i",
+	'&': " and ",
+	'+': " plus ",
+	'#': " sharp ",
+	'@': " at ",
+}
+
+// Slugify turns a string into a lowercase URL path segment. Accents are
+// removed from letters, letters and digits of other scripts are kept, and
+// everything else is replaced by dashes. "Café au Lait" becomes "cafe-au-lait"
+// and "C++" becomes "c-plus-plus".
+func Slugify(str string) string {
+	var sb strings.Builder
+	dash := false
+	writeRune := func(r rune) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteRune('-')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	for _, r := range norm.NFKD.String(str) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		if replacement, ok := transliterations[r]; ok {
+			for _, rr := range replacement {
+				writeRune(rr)
+			}
+			continue
+		}
+
+		writeRune(r)
+	}
+
+	return norm.NFC.String(sb.String())
+}
@@ -0,0 +1,32 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"food":            "food",
+		"Street Food":     "street-food",
+		"Café":            "cafe",
+		"Crème Brûlée":    "creme-brulee",
+		"C++":             "c-plus-plus",
+		"C#":              "c-sharp",
+		"Food/Travel":     "food-travel",
+		"Rock & Roll":     "rock-and-roll",
+		"  --Trimmed--  ": "trimmed",
+		"Straße":          "strasse",
+		"Łódź":            "lodz",
+		"日本語":             "日本語",
+		"Привет, мир":     "привет-мир",
+		"ﬁre":             "fire",
+		"100% Pure":       "100-pure",
+		"?!":              "",
+	}
+
+	for str, expected := range cases {
+		assert.Equal(t, expected, Slugify(str), "Slugify(%q)", str)
+	}
+}
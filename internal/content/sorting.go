@@ -0,0 +1,117 @@
+package content
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The values of sort_by besides "extra.<key>", which sorts by a value in the
+// extra front matter.
+const (
+	SortByDate    = "date"
+	SortByUpdated = "updated"
+	SortByTitle   = "title"
+	SortByWeight  = "weight"
+	sortByExtra   = "extra."
+)
+
+// PageComparator returns the comparison that sorts the pages of a section by
+// sortBy. Dates sort newest first, titles alphabetically, weights and extra
+// values from lowest to highest. Ties are sorted by date and then by path.
+// Pages missing the extra value sort last even when reversed.
+func PageComparator(sortBy string, reverse bool) (func(a, b *WebPage) int, error) {
+	var compare func(a, b *WebPage) int
+	missingLast := func(a, b *WebPage) int { return 0 }
+	switch {
+	case sortBy == SortByDate:
+		compare = func(a, b *WebPage) int {
+			return b.FrontMatter.Date.Compare(a.FrontMatter.Date)
+		}
+	case sortBy == SortByUpdated:
+		compare = func(a, b *WebPage) int {
+			return b.FrontMatter.LastModified().Compare(a.FrontMatter.LastModified())
+		}
+	case sortBy == SortByTitle:
+		compare = func(a, b *WebPage) int {
+			return cmp.Compare(strings.ToLower(a.FrontMatter.Title), strings.ToLower(b.FrontMatter.Title))
+		}
+	case sortBy == SortByWeight:
+		compare = func(a, b *WebPage) int {
+			return cmp.Compare(a.FrontMatter.Weight, b.FrontMatter.Weight)
+		}
+	case strings.HasPrefix(sortBy, sortByExtra) && len(sortBy) > len(sortByExtra):
+		key := sortBy[len(sortByExtra):]
+		missingLast = func(a, b *WebPage) int {
+			return compareMissing(a.FrontMatter.Extra[key], b.FrontMatter.Extra[key])
+		}
+		compare = func(a, b *WebPage) int {
+			return compareExtraValues(a.FrontMatter.Extra[key], b.FrontMatter.Extra[key])
+		}
+	default:
+		return nil, fmt.Errorf("unknown sort_by \"%s\"", sortBy)
+	}
+
+	return func(a, b *WebPage) int {
+		if missing := missingLast(a, b); missing != 0 {
+			return missing
+		}
+
+		result := compare(a, b)
+		if reverse {
+			result = -result
+		}
+
+		return cmp.Or(
+			result,
+			b.FrontMatter.Date.Compare(a.FrontMatter.Date),
+			cmp.Compare(a.MarkdownPath, b.MarkdownPath),
+		)
+	}, nil
+}
+
+// compareMissing sorts missing extra values after present ones.
+func compareMissing(a, b any) int {
+	switch {
+	case (a == nil) == (b == nil):
+		return 0
+	case a == nil:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// compareExtraValues compares numbers, strings and times. Values of different
+// types are compared as text.
+func compareExtraValues(a, b any) int {
+	if a == nil || b == nil {
+		return compareMissing(a, b)
+	}
+
+	aNumber, aIsNumber := extraNumber(a)
+	bNumber, bIsNumber := extraNumber(b)
+	if aIsNumber && bIsNumber {
+		return cmp.Compare(aNumber, bNumber)
+	}
+
+	aTime, aIsTime := a.(time.Time)
+	bTime, bIsTime := b.(time.Time)
+	if aIsTime && bIsTime {
+		return aTime.Compare(bTime)
+	}
+
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func extraNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,63 @@
+package content
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedPaths(t *testing.T, pages []*WebPage, sortBy string, reverse bool) []string {
+	compare, err := PageComparator(sortBy, reverse)
+	assert.NoError(t, err)
+
+	sorted := slices.Clone(pages)
+	slices.SortStableFunc(sorted, compare)
+
+	paths := []string{}
+	for _, page := range sorted {
+		paths = append(paths, page.MarkdownPath)
+	}
+
+	return paths
+}
+
+func TestPageComparator(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, time.February, d, 10, 0, 0, 0, time.UTC)
+	}
+	pages := []*WebPage{
+		NewPage(testMarkdown, "a.md", FrontMatter{
+			Title: "banana", Date: day(1), Updated: day(9), Weight: 3,
+			Extra: map[string]any{"order": int64(10)},
+		}),
+		NewPage(testMarkdown, "b.md", FrontMatter{
+			Title: "Apple", Date: day(2), Weight: 1,
+			Extra: map[string]any{"order": 2.5},
+		}),
+		NewPage(testMarkdown, "c.md", FrontMatter{
+			Title: "cherry", Date: day(3), Weight: 2,
+		}),
+		NewPage(testMarkdown, "d.md", FrontMatter{
+			Title: "Apple", Date: day(3), Weight: 2,
+			Extra: map[string]any{"order": int64(2)},
+		}),
+	}
+
+	assert.Equal(t, []string{"c.md", "d.md", "b.md", "a.md"}, sortedPaths(t, pages, "date", false))
+	assert.Equal(t, []string{"a.md", "b.md", "c.md", "d.md"}, sortedPaths(t, pages, "date", true))
+	assert.Equal(t, []string{"a.md", "c.md", "d.md", "b.md"}, sortedPaths(t, pages, "updated", false))
+	assert.Equal(t, []string{"d.md", "b.md", "a.md", "c.md"}, sortedPaths(t, pages, "title", false))
+	assert.Equal(t, []string{"b.md", "c.md", "d.md", "a.md"}, sortedPaths(t, pages, "weight", false))
+	assert.Equal(t, []string{"a.md", "c.md", "d.md", "b.md"}, sortedPaths(t, pages, "weight", true))
+	assert.Equal(t, []string{"d.md", "b.md", "a.md", "c.md"}, sortedPaths(t, pages, "extra.order", false))
+	assert.Equal(t, []string{"a.md", "b.md", "d.md", "c.md"}, sortedPaths(t, pages, "extra.order", true))
+}
+
+func TestUnknownSortBy(t *testing.T) {
+	for _, sortBy := range []string{"name", "extra.", ""} {
+		_, err := PageComparator(sortBy, false)
+		assert.EqualError(t, err, "unknown sort_by \""+sortBy+"\"")
+	}
+}
@@ -0,0 +1,42 @@
+package content
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileLanguage returns the language of a translation named like about.fr.md
+// if it is one of languages.
+func FileLanguage(path string, languages []string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	lang := strings.TrimPrefix(filepath.Ext(name), ".")
+	if lang != "" && slices.Contains(languages, lang) {
+		return lang
+	}
+
+	return ""
+}
+
+// untranslatedPath removes the language from the path of a translation, so
+// about.fr.md becomes about.md.
+func untranslatedPath(path, language string) string {
+	if language == "" {
+		return path
+	}
+
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, "."+language+ext) + ext
+}
+
+// LanguagePrefix returns the language of a translation, which its path starts
+// with. It is empty for pages in the default language.
+func (p *WebPage) LanguagePrefix() string {
+	return p.language
+}
+
+// TranslationKey returns the path of the Markdown file without its language.
+// It is the same for all the translations of a page.
+func (p *WebPage) TranslationKey() string {
+	return filepath.ToSlash(untranslatedPath(p.MarkdownPath, p.language))
+}
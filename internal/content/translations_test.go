@@ -0,0 +1,71 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLanguage(t *testing.T) {
+	languages := []string{"de", "fr"}
+
+	assert.Equal(t, "fr", FileLanguage("about.fr.md", languages))
+	assert.Equal(t, "de", FileLanguage("posts/day-1/index.de.md", languages))
+	assert.Equal(t, "", FileLanguage("about.md", languages))
+	assert.Equal(t, "", FileLanguage("release.v2.md", languages))
+}
+
+func TestTranslationPaths(t *testing.T) {
+	a := assert.New(t)
+	md := `+++
+title = "À propos"
++++
+
+Bonjour.
+`
+	cases := []struct {
+		markdownPath string
+		sourcePath   string
+		key          string
+	}{
+		{"about.fr.md", "fr/about", "about.md"},
+		{"index.fr.md", "fr", "index.md"},
+		{"posts/day-1/index.fr.md", "fr/posts/day-1", "posts/day-1/index.md"},
+	}
+
+	for _, c := range cases {
+		page, err := ParseTranslation(testMarkdown, c.markdownPath, "fr", []byte(md))
+
+		a.NoError(err)
+		a.Equal("fr", page.LanguagePrefix())
+		a.Equal("fr", page.FrontMatter.Language)
+		a.Equal(c.sourcePath, page.SourcePath())
+		a.Equal(c.sourcePath, page.RenderedPath())
+		a.Equal(c.key, page.TranslationKey())
+	}
+}
+
+func TestTranslationWithPath(t *testing.T) {
+	page, err := ParseTranslation(testMarkdown, "about.fr.md", "fr", []byte(`+++
+title = "À propos"
+path = "a-propos"
++++
+`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/fr/a-propos/", page.RootPath())
+}
+
+func TestTranslationSummaryUsesItsLanguage(t *testing.T) {
+	page, err := ParseTranslation(testMarkdown, "about.fr.md", "fr", []byte(`+++
+title = "À propos"
+summary = 'Il a dit "bonjour".'
++++
+`))
+	assert.NoError(t, err)
+
+	summary, err := page.Summary(0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Il a dit «\u00a0bonjour\u00a0».</p>", summary)
+}
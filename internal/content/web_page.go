@@ -2,23 +2,31 @@ package content
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
+	"html"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"codeberg.org/asartalo/assg/internal/markdown"
 	"codeberg.org/asartalo/assg/internal/template"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
 	"go.abhg.dev/goldmark/frontmatter"
 )
 
 type IndexFields struct {
-	SortBy       string `toml:"sort_by"`
-	Template     string `toml:"template"`
-	PageTemplate string `toml:"page_template"`
-	PaginateBy   int    `toml:"paginate_by"`
-	Taxonomy     string `toml:"taxonomy"`
+	SortBy       string `toml:"sort_by" yaml:"sort_by" json:"sort_by"`
+	SortReverse  bool   `toml:"sort_reverse" yaml:"sort_reverse" json:"sort_reverse"`
+	Recursive    bool   `toml:"recursive" yaml:"recursive" json:"recursive"`
+	Template     string `toml:"template" yaml:"template" json:"template"`
+	PageTemplate string `toml:"page_template" yaml:"page_template" json:"page_template"`
+	PaginateBy   int    `toml:"paginate_by" yaml:"paginate_by" json:"paginate_by"`
+	Taxonomy     string `toml:"taxonomy" yaml:"taxonomy" json:"taxonomy"`
 }
 
 func firstNonEmptyString(strs ...string) string {
@@ -32,17 +40,34 @@ func firstNonEmptyString(strs ...string) string {
 	return ""
 }
 
-// FrontMatter represents the TOML frontmatter of a Markdown file.
+// FrontMatter represents the TOML, YAML or JSON frontmatter of a Markdown file.
 type FrontMatter struct {
-	Title       string              `toml:"title"`
-	Description string              `toml:"description"`
-	Date        time.Time           `toml:"date"`
-	Draft       bool                `toml:"draft"`
-	Summary     string              `toml:"summary"`
-	Taxonomies  map[string][]string `toml:"taxonomies"`
-	Template    string              `toml:"template"`
-	Index       IndexFields         `toml:"index"`
-	Extra       map[string]any      `toml:"extra"`
+	Title       string              `toml:"title" yaml:"title" json:"title"`
+	Description string              `toml:"description" yaml:"description" json:"description"`
+	Date        time.Time           `toml:"date" yaml:"date" json:"date"`
+	Updated     time.Time           `toml:"updated" yaml:"updated" json:"updated"`
+	ExpiryDate  time.Time           `toml:"expiry_date" yaml:"expiry_date" json:"expiry_date"`
+	Draft       bool                `toml:"draft" yaml:"draft" json:"draft"`
+	Weight      int                 `toml:"weight" yaml:"weight" json:"weight"`
+	Language    string              `toml:"language" yaml:"language" json:"language"`
+	Slug        string              `toml:"slug" yaml:"slug" json:"slug"`
+	Path        string              `toml:"path" yaml:"path" json:"path"`
+	Aliases     []string            `toml:"aliases" yaml:"aliases" json:"aliases"`
+	Summary     string              `toml:"summary" yaml:"summary" json:"summary"`
+	Taxonomies  map[string][]string `toml:"taxonomies" yaml:"taxonomies" json:"taxonomies"`
+	Menu        map[string]PageMenu `toml:"menu" yaml:"menu" json:"menu"`
+	Template    string              `toml:"template" yaml:"template" json:"template"`
+	Index       IndexFields         `toml:"index" yaml:"index" json:"index"`
+	Extra       map[string]any      `toml:"extra" yaml:"extra" json:"extra"`
+}
+
+// PageMenu adds a page to a menu.
+type PageMenu struct {
+	// Name defaults to the page's title
+	Name   string `toml:"name" yaml:"name" json:"name"`
+	Weight int    `toml:"weight" yaml:"weight" json:"weight"`
+	// Parent is the name of the menu entry the page is listed under
+	Parent string `toml:"parent" yaml:"parent" json:"parent"`
 }
 
 func (f FrontMatter) HasExtraData(key string) bool {
@@ -54,12 +79,70 @@ func (f FrontMatter) GetExtraData(key string) interface{} {
 	return f.Extra[key]
 }
 
+// WordsPerMinute is the reading speed used to estimate ReadingTime.
+const WordsPerMinute = 200
+
+// moreMarker is the HTML comment that splits the excerpt from the rest of the
+// content.
+var moreMarker = regexp.MustCompile(`^\s*<!--\s*more\s*-->\s*$`)
+
+// findMoreMarker returns the start and end of the first <!-- more --> marker
+// that is an HTML block at the top level of the Markdown source, or nil. A
+// marker in a code block or inside other blocks is left alone.
+func findMoreMarker(md goldmark.Markdown, source []byte) []int {
+	doc := md.Parser().Parse(text.NewReader(source), parser.WithContext(parser.NewContext()))
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		block, ok := node.(*ast.HTMLBlock)
+		if !ok || block.Lines().Len() == 0 {
+			continue
+		}
+
+		start := block.Lines().At(0).Start
+		stop := block.Lines().At(block.Lines().Len() - 1).Stop
+		if block.HasClosure() {
+			stop = block.ClosureLine.Stop
+		}
+
+		if moreMarker.Match(source[start:stop]) {
+			return []int{start, stop}
+		}
+	}
+
+	return nil
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
 // WebPage represents the parsed content of a Markdown file.
 type WebPage struct {
-	FrontMatter    FrontMatter
-	Content        bytes.Buffer
-	MarkdownPath   string
+	FrontMatter  FrontMatter
+	Content      bytes.Buffer
+	MarkdownPath string
+	Headings     []markdown.Heading
+	// Excerpt is the rendered content before the <!-- more --> marker
+	Excerpt string
+	// Resources are the files in a page bundle's directory, relative to it
+	Resources []string
+	// ResourcesPath is the root path the resources are rendered under, which
+	// translations share with the page in the default language
+	ResourcesPath  string
+	WordCount      int
+	ReadingTime    int
 	contentSummary string
+	markdown       goldmark.Markdown
+	renderedPath   *string
+	// language is set for translations, see LanguagePrefix
+	language string
+}
+
+// LastModified returns the updated date, or the date if the page was not
+// updated.
+func (f FrontMatter) LastModified() time.Time {
+	if f.Updated.IsZero() {
+		return f.Date
+	}
+
+	return f.Updated
 }
 
 func (p *WebPage) DateUnixEpoch() int64 {
@@ -68,11 +151,13 @@ func (p *WebPage) DateUnixEpoch() int64 {
 
 // IsIndex returns true if the page is an index page.
 func (p *WebPage) IsIndex() bool {
-	return p.FrontMatter.Index.SortBy != ""
+	return p.FrontMatter.Index.SortBy != "" || p.IsTaxonomy()
 }
 
+// IsTaxonomy returns true for the page listing the terms of a taxonomy, which
+// doesn't need a sort_by.
 func (p *WebPage) IsTaxonomy() bool {
-	return p.IsIndex() && p.TaxonomyType() != ""
+	return p.TaxonomyType() != ""
 }
 
 func (p *WebPage) TaxonomyType() string {
@@ -92,57 +177,229 @@ func (p *WebPage) RootPath() string {
 	return RootPath(filepath.ToSlash(p.RenderedPath()))
 }
 
-func (p *WebPage) Summary() (string, error) {
+// Summary returns the summary from the front matter, the excerpt, the
+// description or the first paragraph of the content, whichever comes first.
+// The first paragraph is cut to maxWords words unless maxWords is 0.
+func (p *WebPage) Summary(maxWords int) (string, error) {
 	if p.contentSummary != "" {
 		return p.contentSummary, nil
 	}
 
+	if p.FrontMatter.Summary == "" && p.Excerpt != "" {
+		p.contentSummary = p.Excerpt
+		return p.contentSummary, nil
+	}
+
 	summaryAvailable := firstNonEmptyString(p.FrontMatter.Summary, p.FrontMatter.Description)
 	rendered := bytes.Buffer{}
 	if summaryAvailable != "" {
 		context := parser.NewContext()
-		if err := markdown.Parser.Convert([]byte(summaryAvailable), &rendered, parser.WithContext(context)); err != nil {
+		markdown.SetPageLanguage(context, p.FrontMatter.Language)
+		if err := p.markdown.Convert([]byte(summaryAvailable), &rendered, parser.WithContext(context)); err != nil {
 			return "", err
 		}
 
 		p.contentSummary = strings.TrimSpace(rendered.String())
 	} else {
-		p.contentSummary = template.FirstParagraphFromString(p.Content.String())
+		p.contentSummary = template.ExcerptFromString(p.Content.String(), maxWords)
 	}
 
 	return p.contentSummary, nil
 }
 
-// ParsePage parses a Markdown file with TOML frontmatter.
-func ParsePage(path string, content []byte) (*WebPage, error) {
-	var buf bytes.Buffer
+// CountWords counts the words in the text of rendered HTML.
+func CountWords(htmlContent string) int {
+	return len(strings.Fields(html.UnescapeString(htmlTags.ReplaceAllString(htmlContent, " "))))
+}
+
+// ReadingTime estimates the minutes it takes to read wordCount words.
+func ReadingTime(wordCount int) int {
+	if wordCount == 0 {
+		return 0
+	}
+
+	return (wordCount + WordsPerMinute - 1) / WordsPerMinute
+}
+
+// NewPage creates a page that has no Markdown source of its own, like the
+// generated taxonomy term pages.
+func NewPage(md goldmark.Markdown, path string, frontMatter FrontMatter) *WebPage {
+	return NewTranslationPage(md, path, "", frontMatter)
+}
+
+// NewTranslationPage creates a page like NewPage. If language is not empty, the
+// page is a translation in that language.
+func NewTranslationPage(md goldmark.Markdown, path string, language string, frontMatter FrontMatter) *WebPage {
+	return &WebPage{FrontMatter: frontMatter, MarkdownPath: path, markdown: md, language: language}
+}
+
+// ParsePage parses a Markdown file with TOML, YAML or JSON frontmatter using
+// the site's Markdown parser (see markdown.New).
+func ParsePage(md goldmark.Markdown, path string, content []byte) (*WebPage, error) {
+	return ParseTranslation(md, path, "", content)
+}
+
+// ParseTranslation parses a Markdown file like ParsePage. If language is not
+// empty, the file is a translation in that language (see FileLanguage).
+func ParseTranslation(md goldmark.Markdown, path string, language string, content []byte) (*WebPage, error) {
+	fm := FrontMatter{}
 	context := parser.NewContext()
-	if err := markdown.Parser.Convert(content, &buf, parser.WithContext(context)); err != nil {
+	markdown.SetPageLanguage(context, language)
+
+	// JSON frontmatter is not delimited so it is split off before parsing
+	body, jsonFrontMatter, err := splitJSONFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode front matter: %v", err)
+	}
+
+	if jsonFrontMatter != nil {
+		if err := decodeJSONFrontMatter(jsonFrontMatter, &fm); err != nil {
+			return nil, fmt.Errorf("failed to decode front matter: %v", err)
+		}
+		markdown.SetPageLanguage(context, cmp.Or(language, fm.Language))
+	}
+
+	bundlePath := ""
+	if IsBundlePath(untranslatedPath(path, language)) {
+		bundlePath = RootPath(filepath.ToSlash(filepath.Dir(path)))
+		markdown.SetBasePath(context, bundlePath)
+	}
+
+	excerpt := ""
+	if loc := findMoreMarker(md, body); loc != nil {
+		excerptContext := parser.NewContext()
+		markdown.SetPageLanguage(excerptContext, cmp.Or(language, fm.Language))
+		markdown.SetBasePath(excerptContext, bundlePath)
+
+		var excerptBuf bytes.Buffer
+		if err := md.Convert(body[:loc[0]], &excerptBuf, parser.WithContext(excerptContext)); err != nil {
+			return nil, err
+		}
+
+		excerpt = strings.TrimSpace(excerptBuf.String())
+		body = append(body[:loc[0]:loc[0]], body[loc[1]:]...)
+	}
+
+	var buf bytes.Buffer
+	if err := md.Convert(body, &buf, parser.WithContext(context)); err != nil {
 		return nil, err
 	}
 
-	// Extract frontmatter from the context
-	fm := FrontMatter{}
+	// Extract TOML or YAML frontmatter from the context
 	if fmi := frontmatter.Get(context); fmi != nil {
 		if err := fmi.Decode(&fm); err != nil {
 			return nil, fmt.Errorf("failed to decode front matter: %v", err)
 		}
 	}
+	fm.Extra = normalizeExtra(fm.Extra)
+	if language != "" {
+		fm.Language = language
+	}
 
-	return &WebPage{FrontMatter: fm, Content: buf, MarkdownPath: path}, nil
+	wordCount := CountWords(buf.String())
+
+	return &WebPage{
+		FrontMatter:  fm,
+		Content:      buf,
+		MarkdownPath: path,
+		Headings:     markdown.GetHeadings(context),
+		Excerpt:      excerpt,
+		WordCount:    wordCount,
+		ReadingTime:  ReadingTime(wordCount),
+		markdown:     md,
+		language:     language,
+	}, nil
 }
 
-func (p *WebPage) RenderedPath() string {
+// IsBundlePath returns true if the Markdown file at path is the index.md of a
+// page bundle, a directory holding a page and its resources.
+func IsBundlePath(path string) bool {
+	return path != "index.md" && filepath.Base(path) == "index.md"
+}
+
+// IsBundle returns true if the page is the index.md of a page bundle.
+func (p *WebPage) IsBundle() bool {
+	return IsBundlePath(untranslatedPath(p.MarkdownPath, p.language))
+}
+
+// SourcePath returns the path derived from the location of the Markdown file.
+// The paths of translations start with their language.
+func (p *WebPage) SourcePath() string {
+	markdownPath := untranslatedPath(p.MarkdownPath, p.language)
+
 	// if the file is named index.md, we want to render it as the root index.html (e.g. /index.html)
-	if p.MarkdownPath == "index.md" {
-		return ""
+	if markdownPath == "index.md" {
+		return p.language
+	}
+
+	// a bundle's index.md is rendered at the bundle directory
+	if p.IsBundle() {
+		return filepath.Join(p.language, filepath.Dir(markdownPath))
+	}
+
+	extension := filepath.Ext(markdownPath)
+	lastDotIndex := len(markdownPath) - len(extension)
+	return filepath.Join(p.language, markdownPath[:lastDotIndex])
+}
+
+// isHome returns true if the page is the root index.md or its translation.
+func (p *WebPage) isHome() bool {
+	return untranslatedPath(p.MarkdownPath, p.language) == "index.md"
+}
+
+// PathUnder returns the path of the page when its parent directory is
+// rendered at parentPath. The front matter path replaces the whole path and
+// the slug replaces the last part.
+func (p *WebPage) PathUnder(parentPath string) string {
+	if p.isHome() {
+		return p.language
 	}
 
-	extension := filepath.Ext(p.MarkdownPath)
-	lastDotIndex := len(p.MarkdownPath) - len(extension)
-	return p.MarkdownPath[:lastDotIndex]
+	if p.FrontMatter.Path != "" {
+		return filepath.Join(p.language, filepath.FromSlash(strings.Trim(p.FrontMatter.Path, "/")))
+	}
+
+	sourcePath := p.SourcePath()
+
+	name := filepath.Base(sourcePath)
+	if slug := strings.Trim(p.FrontMatter.Slug, "/"); slug != "" {
+		name = slug
+	}
+
+	return filepath.Join(parentPath, name)
+}
+
+// SetRenderedPath sets the final path of the page once the paths of its
+// parents are known.
+func (p *WebPage) SetRenderedPath(path string) {
+	p.renderedPath = &path
+}
+
+// RenderedPath returns the path the page is rendered at.
+func (p *WebPage) RenderedPath() string {
+	if p.renderedPath != nil {
+		return *p.renderedPath
+	}
+
+	parentPath := filepath.Dir(p.SourcePath())
+	if parentPath == "." {
+		parentPath = ""
+	}
+
+	return p.PathUnder(parentPath)
 }
 
 func (p *WebPage) IsDraft() bool {
 	return p.FrontMatter.Draft
 }
+
+// IsScheduled returns true if the page's date is after now.
+func (p *WebPage) IsScheduled(now time.Time) bool {
+	return p.FrontMatter.Date.After(now)
+}
+
+// IsExpired returns true if the page's expiry date is not after now.
+func (p *WebPage) IsExpired(now time.Time) bool {
+	expiry := p.FrontMatter.ExpiryDate
+	return !expiry.IsZero() && !expiry.After(now)
+}
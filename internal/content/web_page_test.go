@@ -1,12 +1,27 @@
 package content
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"codeberg.org/asartalo/assg/internal/config"
+	"codeberg.org/asartalo/assg/internal/markdown"
 	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark"
 )
 
+var testMarkdown = newTestMarkdown()
+
+func newTestMarkdown() goldmark.Markdown {
+	md, err := markdown.New(&config.Config{Markdown: config.DefaultMarkdownConfig()})
+	if err != nil {
+		panic(err)
+	}
+
+	return md
+}
+
 func TestParsingPageWithNoIndex(t *testing.T) {
 	a := assert.New(t)
 	md := `+++
@@ -17,7 +32,7 @@ description = "Test page description"
 
 Hello.
 `
-	page, err := ParsePage("test.md", []byte(md))
+	page, err := ParsePage(testMarkdown, "test.md", []byte(md))
 
 	a.NoError(err)
 	a.Equal("Test Page", page.FrontMatter.Title)
@@ -42,7 +57,7 @@ number = 1
 
 Extra extra.
 `
-	page, err := ParsePage("test.md", []byte(md))
+	page, err := ParsePage(testMarkdown, "test.md", []byte(md))
 
 	a.NoError(err)
 	a.Equal("Extra Test Page", page.FrontMatter.Title)
@@ -71,7 +86,7 @@ paginate_by = 10
 
 Index page content.
 `
-	page, err := ParsePage("index.md", []byte(md))
+	page, err := ParsePage(testMarkdown, "index.md", []byte(md))
 
 	a.NoError(err)
 	a.Equal("Test Index Page", page.FrontMatter.Title)
@@ -84,3 +99,168 @@ Index page content.
 	a.Equal("date", page.FrontMatter.Index.SortBy)
 	a.Equal(10, page.FrontMatter.Index.PaginateBy)
 }
+
+func TestParsingPageWithMoreMarker(t *testing.T) {
+	a := assert.New(t)
+	md := `+++
+title = "Excerpt Page"
+description = "Not the excerpt"
++++
+
+The "first" part.
+
+<!-- more -->
+
+The rest.
+`
+	page, err := ParsePage(testMarkdown, "test.md", []byte(md))
+
+	a.NoError(err)
+	a.Equal("<p>The “first” part.</p>", page.Excerpt)
+	a.Equal("<p>The “first” part.</p>\n<p>The rest.</p>\n", page.Content.String())
+
+	summary, err := page.Summary(0)
+	a.NoError(err)
+	a.Equal("<p>The “first” part.</p>", summary)
+}
+
+func TestMoreMarkerInCodeBlock(t *testing.T) {
+	a := assert.New(t)
+	md := `+++
+title = "Test"
++++
+
+Put this in a post:
+
+` + "```html" + `
+<!-- more -->
+` + "```" + `
+
+<!-- more -->
+
+The rest.
+`
+	page, err := ParsePage(testMarkdown, "test.md", []byte(md))
+
+	a.NoError(err)
+	a.Equal(
+		"<p>Put this in a post:</p>\n<pre><code class=\"language-html\">&lt;!-- more --&gt;\n</code></pre>",
+		page.Excerpt,
+	)
+	a.Contains(page.Content.String(), "<p>The rest.</p>")
+	a.NotContains(page.Content.String(), "<!-- more -->\n<p>")
+}
+
+func TestSummaryLimitsFirstParagraphWords(t *testing.T) {
+	a := assert.New(t)
+	md := `+++
+title = "Long Page"
++++
+
+One *two* three four five.
+
+Six.
+`
+	page, err := ParsePage(testMarkdown, "test.md", []byte(md))
+	a.NoError(err)
+
+	summary, err := page.Summary(3)
+	a.NoError(err)
+	a.Equal("<p>One two three ...</p>", summary)
+}
+
+func TestWordCountAndReadingTime(t *testing.T) {
+	a := assert.New(t)
+	md := "+++\ntitle = \"Counted\"\n+++\n\n" + strings.Repeat("word ", 401) + "\n\n## A &amp; B\n"
+	page, err := ParsePage(testMarkdown, "test.md", []byte(md))
+
+	a.NoError(err)
+	a.Equal(404, page.WordCount)
+	a.Equal(3, page.ReadingTime)
+	a.Equal(0, ReadingTime(0))
+}
+
+func TestParsingPageBundle(t *testing.T) {
+	a := assert.New(t)
+	md := `+++
+title = "Bundle"
++++
+
+![Photo](photo.jpg) and [notes](../notes/).
+`
+	page, err := ParsePage(testMarkdown, "posts/day-1/index.md", []byte(md))
+
+	a.NoError(err)
+	a.True(page.IsBundle())
+	a.Equal("posts/day-1", page.RenderedPath())
+	a.Equal("/posts/day-1/", page.RootPath())
+	a.Equal(
+		"<p><img src=\"/posts/day-1/photo.jpg\" alt=\"Photo\" /> and <a href=\"/posts/notes/\">notes</a>.</p>\n",
+		page.Content.String(),
+	)
+}
+
+func TestRootIndexIsNotABundle(t *testing.T) {
+	page, err := ParsePage(testMarkdown, "index.md", []byte("[a](b.html)\n"))
+
+	assert.NoError(t, err)
+	assert.False(t, page.IsBundle())
+	assert.Equal(t, "", page.RenderedPath())
+	assert.Equal(t, "<p><a href=\"b.html\">a</a></p>\n", page.Content.String())
+}
+
+func TestPagePaths(t *testing.T) {
+	cases := []struct {
+		name         string
+		markdownPath string
+		frontMatter  FrontMatter
+		parentPath   string
+		expected     string
+	}{
+		{"from file", "posts/day-1.md", FrontMatter{}, "posts", "posts/day-1"},
+		{"under moved parent", "posts/day-1.md", FrontMatter{}, "blog", "blog/day-1"},
+		{"slug", "posts/day-1.md", FrontMatter{Slug: "first-day"}, "posts", "posts/first-day"},
+		{"bundle slug", "posts/day-1/index.md", FrontMatter{Slug: "first"}, "blog", "blog/first"},
+		{"path", "posts/day-1.md", FrontMatter{Path: "/info/about/", Slug: "x"}, "blog", "info/about"},
+		{"root index", "index.md", FrontMatter{Slug: "home"}, "", ""},
+	}
+
+	for _, c := range cases {
+		page := NewPage(testMarkdown, c.markdownPath, c.frontMatter)
+		assert.Equal(t, c.expected, page.PathUnder(c.parentPath), c.name)
+	}
+}
+
+func TestTaxonomyPageNeedsNoSorting(t *testing.T) {
+	page := NewPage(testMarkdown, "tags.md", FrontMatter{Index: IndexFields{Taxonomy: "tags"}})
+	assert.True(t, page.IsTaxonomy())
+	assert.True(t, page.IsIndex())
+
+	translation := NewTranslationPage(testMarkdown, "tags.fr.md", "fr", FrontMatter{Index: IndexFields{Taxonomy: "tags"}})
+	assert.Equal(t, "fr", translation.LanguagePrefix())
+	assert.Equal(t, "fr/tags", translation.RenderedPath())
+}
+
+func TestRenderedPathCanBeSet(t *testing.T) {
+	page := NewPage(testMarkdown, "posts/day-1.md", FrontMatter{Slug: "first-day"})
+	assert.Equal(t, "posts/first-day", page.RenderedPath())
+	assert.Equal(t, "posts/day-1", page.SourcePath())
+
+	page.SetRenderedPath("blog/first-day")
+	assert.Equal(t, "blog/first-day", page.RenderedPath())
+	assert.Equal(t, "/blog/first-day/", page.RootPath())
+}
+
+func TestScheduledAndExpiredPages(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	page := NewPage(testMarkdown, "page.md", FrontMatter{Date: now})
+	assert.False(t, page.IsScheduled(now))
+	assert.False(t, page.IsExpired(now))
+
+	page.FrontMatter.Date = now.Add(time.Minute)
+	assert.True(t, page.IsScheduled(now))
+
+	page.FrontMatter.ExpiryDate = now
+	assert.True(t, page.IsExpired(now))
+	assert.False(t, page.IsExpired(now.Add(-time.Second)))
+}
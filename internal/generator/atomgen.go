@@ -1,11 +1,17 @@
 package generator
 
 import (
+	"cmp"
 	"encoding/xml"
 	"fmt"
+	"html"
 	"io"
+	"maps"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -57,6 +63,9 @@ type FeedEntry struct {
 	Summary   *FeedEntrySummary `xml:"summary,omitempty"`
 	Authors   []*FeedAuthor
 	Links     []*FeedLink
+	// WordCount and ReadingTime are ASSG extension elements
+	WordCount   int `xml:"https://codeberg.org/asartalo/assg wordCount"`
+	ReadingTime int `xml:"https://codeberg.org/asartalo/assg readingTime"`
 }
 
 type FeedContent struct {
@@ -128,7 +137,16 @@ func (ag *AtomGenerator) defaultFeedAuthor() *FeedAuthor {
 
 type configAndFeed struct {
 	config config.ContentFeed
-	feed   *Feed
+	// language is the language prefix of the feed's pages
+	language string
+	feed     *Feed
+	pages    []*content.WebPage
+}
+
+// feedLanguages returns the language prefixes feeds are written for, starting
+// with the default language's "".
+func (ag *AtomGenerator) feedLanguages() []string {
+	return append([]string{""}, ag.Config.TranslationLanguages()...)
 }
 
 func (ag *AtomGenerator) GenerateFeeds(now time.Time) error {
@@ -140,37 +158,43 @@ func (ag *AtomGenerator) GenerateFeeds(now time.Time) error {
 
 	cNFs := []configAndFeed{}
 
-	for _, conf := range ag.Config.FeedsForContent {
-		atomUrl := ag.feedUrl(conf)
-		title := conf.Title
-		if title == "" {
-			title = mg.Config.Title
+	for _, language := range ag.feedLanguages() {
+		languageConfig := mg.Config.Languages[language]
+		alternateUrl := mg.SiteUrlNoTrailingslash()
+		if language != "" {
+			alternateUrl = mg.FullUrl(content.RootPath(language))
 		}
 
-		cNFs = append(cNFs, configAndFeed{
-			config: conf,
-			feed: &Feed{
-				Xmlns:     "http://www.w3.org/2005/Atom",
-				Lang:      "en",
-				Title:     title,
-				Subtitle:  mg.Config.Description,
-				Id:        atomUrl,
-				Generator: &FeedGenerator{Uri: "https://codeberg.org/asartalo/assg", Name: "ASSG"},
-				Updated:   FeedDateTime(now),
-				Links: []*FeedLink{
-					{
-						Rel:  "self",
-						Type: "application/atom+xml",
-						Href: atomUrl,
-					},
-					{
-						Rel:  "alternate",
-						Type: "text/html",
-						Href: mg.SiteUrlNoTrailingslash(),
+		for _, conf := range ag.Config.FeedsForContent {
+			atomUrl := ag.feedUrl(conf, language)
+			title := cmp.Or(conf.Title, languageConfig.Title, mg.Config.Title)
+
+			cNFs = append(cNFs, configAndFeed{
+				config:   conf,
+				language: language,
+				feed: &Feed{
+					Xmlns:     "http://www.w3.org/2005/Atom",
+					Lang:      cmp.Or(language, mg.Config.SiteLanguage()),
+					Title:     title,
+					Subtitle:  cmp.Or(languageConfig.Description, mg.Config.Description),
+					Id:        atomUrl,
+					Generator: &FeedGenerator{Uri: "https://codeberg.org/asartalo/assg", Name: "ASSG"},
+					Updated:   FeedDateTime(now),
+					Links: []*FeedLink{
+						{
+							Rel:  "self",
+							Type: "application/atom+xml",
+							Href: atomUrl,
+						},
+						{
+							Rel:  "alternate",
+							Type: "text/html",
+							Href: alternateUrl,
+						},
 					},
 				},
-			},
-		})
+			})
+		}
 	}
 
 	for _, page := range mg.hierarchy.SortedPages() {
@@ -178,64 +202,153 @@ func (ag *AtomGenerator) GenerateFeeds(now time.Time) error {
 			continue
 		}
 
-		ag.Printf("Creating entry for '%s'\n", page.RootPath())
+		for i, cNF := range cNFs {
+			if cNF.language == page.LanguagePrefix() && ag.includedInFeed(cNF.config, page) {
+				ag.Printf("Include page '%s' in %s\n", page.RootPath(), cNF.config.Title)
+				cNFs[i].pages = append(cNFs[i].pages, page)
+			}
+		}
+	}
 
-		entry, err := ag.createFeedEntry(page)
-		if err != nil {
-			return err
+	entries := make(map[*content.WebPage]*FeedEntry)
+	for _, cNF := range cNFs {
+		// a feed of a single section follows the section's order
+		if section := ag.feedSection(cNF.config, cNF.language); section != nil {
+			slices.SortStableFunc(cNF.pages, mg.hierarchy.ChildComparator(section))
 		}
 
-		for _, cNF := range cNFs {
-			if ag.includedInFeed(cNF.config, page) {
-				ag.Printf("Include page in %s\n", cNF.config.Title)
-				cNF.feed.Entries = append(cNF.feed.Entries, entry)
+		for _, page := range cNF.pages {
+			entry, ok := entries[page]
+			if !ok {
+				ag.Printf("Creating entry for '%s'\n", page.RootPath())
+
+				var err error
+				entry, err = ag.createFeedEntry(page)
+				if err != nil {
+					return err
+				}
+				entries[page] = entry
 			}
-		}
 
+			cNF.feed.Entries = append(cNF.feed.Entries, entry)
+		}
 	}
 
 	for _, cNF := range cNFs {
-		atomFilePath := mg.OutputPath(ag.feedFileName(cNF.config))
-		atomFile, err := os.OpenFile(atomFilePath, os.O_RDWR|os.O_CREATE, 0600)
+		err := ag.writeFeed(cNF.feed, ag.feedFileName(cNF.config, cNF.language))
 		if err != nil {
 			return err
 		}
+	}
 
-		ag.Printf("Writing \"%s\" feed to %s\n", cNF.config.Title, atomFilePath)
-		err = cNF.feed.WriteXML(atomFile)
-		if err != nil {
-			return err
+	return ag.generateTermFeeds(now, entries)
+}
+
+// generateTermFeeds writes a feed for each term of the taxonomies with
+// `feed = true`, like tags/food/atom.xml.
+func (ag *AtomGenerator) generateTermFeeds(now time.Time, entries map[*content.WebPage]*FeedEntry) error {
+	mg := ag.mg
+	keys := slices.Sorted(maps.Keys(mg.hierarchy.TaxonomyPage))
+	for _, key := range keys {
+		taxonomyPage := mg.hierarchy.GetTaxonomyPage(key)
+		taxonomyConfig, ok := mg.Config.FindTaxonomy(taxonomyPage.TaxonomyType())
+		if !ok || !taxonomyConfig.Feed {
+			continue
 		}
 
-		err = atomFile.Close()
-		if err != nil {
-			return err
+		language := taxonomyPage.LanguagePrefix()
+		languageConfig := mg.Config.Languages[language]
+		for _, ttc := range mg.GetAllTaxonomyTerms(key) {
+			ag.Printf("Creating feed for the term '%s' of %s\n", ttc.Term, key)
+			feed := &Feed{
+				Xmlns:     "http://www.w3.org/2005/Atom",
+				Lang:      cmp.Or(language, mg.Config.SiteLanguage()),
+				Title:     ag.termFeedTitle(ttc.Name, language),
+				Subtitle:  cmp.Or(languageConfig.Description, mg.Config.Description),
+				Id:        ttc.FeedUrl,
+				Generator: &FeedGenerator{Uri: "https://codeberg.org/asartalo/assg", Name: "ASSG"},
+				Updated:   FeedDateTime(now),
+				Links: []*FeedLink{
+					{
+						Rel:  "self",
+						Type: "application/atom+xml",
+						Href: ttc.FeedUrl,
+					},
+					{
+						Rel:  "alternate",
+						Type: "text/html",
+						Href: ttc.Permalink,
+					},
+				},
+			}
+
+			for _, page := range mg.hierarchy.GetTaxonomyTerms(key)[ttc.Term] {
+				entry, ok := entries[page]
+				if !ok {
+					var err error
+					entry, err = ag.createFeedEntry(page)
+					if err != nil {
+						return err
+					}
+					entries[page] = entry
+				}
+
+				feed.Entries = append(feed.Entries, entry)
+			}
+
+			err := ag.writeFeed(feed, ag.termFeedFileName(taxonomyPage, ttc.Term))
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+func (ag *AtomGenerator) writeFeed(feed *Feed, fileName string) error {
+	atomFilePath := ag.mg.OutputPath(fileName)
+	err := os.MkdirAll(filepath.Dir(atomFilePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	atomFile, err := os.OpenFile(atomFilePath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	ag.Printf("Writing \"%s\" feed to %s\n", feed.Title, atomFilePath)
+	err = feed.WriteXML(atomFile)
+	if err != nil {
+		return err
+	}
+
+	return atomFile.Close()
+}
+
 func (ag *AtomGenerator) createFeedEntry(page *content.WebPage) (*FeedEntry, error) {
 	g := ag.mg
 	pageUrl := g.FullUrl(page.RootPath())
 
 	item := &FeedEntry{
-		Lang:  "en",
+		Lang:  g.pg.pageLanguage(page),
 		Title: page.FrontMatter.Title,
 		Links: []*FeedLink{
 			{Rel: "alternate", Type: "text/html", Href: pageUrl},
 		},
-		Published: FeedDateTime(page.FrontMatter.Date),
-		Updated:   FeedDateTime(page.FrontMatter.Date),
-		Id:        pageUrl,
-		Authors:   []*FeedAuthor{ag.defaultFeedAuthor()},
+		Published:   FeedDateTime(page.FrontMatter.Date),
+		Updated:     FeedDateTime(page.FrontMatter.LastModified()),
+		Id:          pageUrl,
+		Authors:     []*FeedAuthor{ag.defaultFeedAuthor()},
+		WordCount:   page.WordCount,
+		ReadingTime: page.ReadingTime,
 	}
 
 	contentLength := page.Content.Len()
 	// If the content is too long, or empty, use the summary
 	if contentLength > 500 || contentLength == 0 {
-		summary, err := page.Summary()
+		summary, err := page.Summary(ag.Config.SummaryLength)
 		if err != nil {
 			return nil, err
 		}
@@ -255,32 +368,66 @@ func (ag *AtomGenerator) createFeedEntry(page *content.WebPage) (*FeedEntry, err
 	return item, nil
 }
 
+// AtomLinks returns the links to the feeds in the language of the page being
+// rendered.
 func (ag *AtomGenerator) AtomLinks() string {
 	var sb strings.Builder
+	language := ag.mg.renderingLanguage()
 	for _, feed := range ag.Config.FeedsForContent {
 		sb.WriteString(fmt.Sprintf(
 			`<link rel="alternate" title="%s" type="application/atom+xml" href="%s">`,
-			ag.feedTitle(feed),
-			ag.feedUrl(feed),
+			ag.feedTitle(feed, language),
+			ag.feedUrl(feed, language),
 		))
 	}
 
 	return sb.String()
 }
 
-func (ag *AtomGenerator) feedTitle(feedConfig config.ContentFeed) string {
+// TermAtomLink returns the link to the feed of the taxonomy term being
+// rendered, on each of its pages, or nothing on other pages.
+func (ag *AtomGenerator) TermAtomLink() string {
+	mg := ag.mg
+	if mg.renderingTerm == "" {
+		return ""
+	}
+
+	ttc, ok := mg.ensurePopulatedTaxonomyCache(mg.renderingTaxonomy)[mg.renderingTerm]
+	if !ok || ttc.FeedUrl == "" {
+		return ""
+	}
+
+	taxonomyPage := mg.hierarchy.GetTaxonomyPage(mg.renderingTaxonomy)
+	return fmt.Sprintf(
+		`<link rel="alternate" title="%s" type="application/atom+xml" href="%s">`,
+		html.EscapeString(ag.termFeedTitle(ttc.Name, taxonomyPage.LanguagePrefix())),
+		html.EscapeString(ttc.FeedUrl),
+	)
+}
+
+func (ag *AtomGenerator) termFeedTitle(name, language string) string {
+	return fmt.Sprintf(
+		"%s - %s",
+		cmp.Or(ag.Config.Languages[language].Title, ag.Config.Title),
+		titleCase(name),
+	)
+}
+
+func (ag *AtomGenerator) feedTitle(feedConfig config.ContentFeed, language string) string {
 	if feedConfig.Title == "" {
-		return fmt.Sprintf("%s Feed", ag.Config.Title)
+		return fmt.Sprintf("%s Feed", cmp.Or(ag.Config.Languages[language].Title, ag.Config.Title))
 	}
 
 	return feedConfig.Title
 }
 
-func (ag *AtomGenerator) feedUrl(feedConfig config.ContentFeed) string {
-	return ag.mg.FullUrl(ag.feedFileName(feedConfig))
+func (ag *AtomGenerator) feedUrl(feedConfig config.ContentFeed, language string) string {
+	return ag.mg.FullUrl(ag.feedFileName(feedConfig, language))
 }
 
-func (ag *AtomGenerator) feedFileName(feedConfig config.ContentFeed) string {
+// feedFileName returns the path of a feed. The feeds of translations are in
+// their language's directory.
+func (ag *AtomGenerator) feedFileName(feedConfig config.ContentFeed, language string) string {
 	var name string
 	if feedConfig.Name == "all" {
 		name = "atom"
@@ -288,11 +435,38 @@ func (ag *AtomGenerator) feedFileName(feedConfig config.ContentFeed) string {
 		name = feedConfig.Name
 	}
 
-	return fmt.Sprintf("%s.xml", name)
+	return path.Join(language, fmt.Sprintf("%s.xml", name))
+}
+
+// termFeedFileName returns the path of the feed of a taxonomy term, next to
+// the term's page.
+func (ag *AtomGenerator) termFeedFileName(taxonomyPage *content.WebPage, term string) string {
+	slug := ag.mg.hierarchy.TermSlug(taxonomyPage.TaxonomyType(), term)
+	return path.Join(filepath.ToSlash(taxonomyPage.RenderedPath()), slug, "atom.xml")
+}
+
+// feedSection returns the index page of the feed's only included section.
+func (ag *AtomGenerator) feedSection(feedConfig config.ContentFeed, language string) *content.WebPage {
+	inclusions := feedConfig.Inclusions()
+	if len(inclusions) != 1 {
+		return nil
+	}
+
+	page := ag.mg.hierarchy.GetPage(filepath.Join(language, filepath.FromSlash(strings.Trim(inclusions[0], "/"))))
+	if page == nil || !page.IsIndex() {
+		return nil
+	}
+
+	return page
 }
 
+// includedInFeed checks the page's path, without its language, against the
+// feed's inclusions and exclusions.
 func (ag *AtomGenerator) includedInFeed(feedConfig config.ContentFeed, page *content.WebPage) bool {
 	path := page.RenderedPath()
+	if language := page.LanguagePrefix(); language != "" {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, language), string(filepath.Separator))
+	}
 	if feedConfig.IncludeAllInitially() {
 		for _, exPrefix := range feedConfig.Exclusions() {
 			if exPrefix != "" && strings.HasPrefix(path, exPrefix) {
@@ -15,7 +15,10 @@ import (
 
 	"codeberg.org/asartalo/assg/internal/config"
 	"codeberg.org/asartalo/assg/internal/content"
+	"codeberg.org/asartalo/assg/internal/i18n"
+	"codeberg.org/asartalo/assg/internal/markdown"
 	"codeberg.org/asartalo/assg/internal/template"
+	"github.com/yuin/goldmark"
 )
 
 type TermTTC map[string]*TaxonomyTermContent
@@ -23,13 +26,28 @@ type TermTTC map[string]*TaxonomyTermContent
 type Generator struct {
 	Config        *config.Config
 	Tmpl          *template.Engine
+	markdown      goldmark.Markdown
 	hierarchy     *ContentHierarchy
 	feedAuthor    *FeedAuthor
 	taxonomyCache map[string]TermTTC
 	verbose       bool
 	renderedPaths []string
-	ag            *AtomGenerator
-	pg            *PageGenerator
+	// outputFiles maps the files written to the output directory to what
+	// wrote them
+	outputFiles map[string]string
+	menus       map[string][]*menuNode
+	// renderingPath is the root path of the page being rendered
+	renderingPath string
+	// renderingTaxonomy and renderingTerm are the taxonomy key (see
+	// taxonomyKey) and term of the term page being rendered
+	renderingTaxonomy string
+	renderingTerm     string
+	site              SiteContent
+	i18n              *i18n.Translations
+	// missingTranslations holds the missing i18n keys already reported
+	missingTranslations map[string]bool
+	ag                  *AtomGenerator
+	pg                  *PageGenerator
 }
 
 func defineFuncs(generator *Generator) htmltpl.FuncMap {
@@ -47,25 +65,59 @@ func defineFuncs(generator *Generator) htmltpl.FuncMap {
 
 		return IndexTemplateContent{
 			TemplateContent: templateContent,
+			Subsections:     generator.pg.GetSubsections(page),
 		}
 	}
 
+	funcMap["ancestors"] = func(path string) []Breadcrumb {
+		return generator.pg.Ancestors(path)
+	}
+
+	funcMap["breadcrumbs"] = func(path string) []Breadcrumb {
+		return generator.pg.Breadcrumbs(path)
+	}
+
+	funcMap["breadcrumbsJsonLd"] = func(path string) (htmltpl.HTML, error) {
+		return BreadcrumbListJsonLd(generator.pg.Breadcrumbs(path))
+	}
+
+	funcMap["menu"] = func(name string) []MenuItem {
+		return generator.Menu(name)
+	}
+
+	funcMap["data"] = func(dataPath string) (any, error) {
+		return generator.Data(dataPath)
+	}
+
+	funcMap["t"] = func(key string, count ...int) string {
+		return generator.Translate(key, count...)
+	}
+
 	funcMap["taxonomyTerms"] = func(taxonomy string) []*TaxonomyTermContent {
-		return generator.GetAllTaxonomyTerms(taxonomy)
+		return generator.GetTaxonomyTermTree(taxonomyKey(generator.renderingLanguage(), taxonomy))
 	}
 
 	funcMap["pageTaxonomy"] = func(path, taxonomy string) []*TaxonomyTermContent {
 		return generator.GetTaxonomyTermsForPage(path, taxonomy)
 	}
 
+	funcMap["tableOfContents"] = func(toc []*markdown.Heading, minLevel, maxLevel int) htmltpl.HTML {
+		tree := markdown.HeadingTree(markdown.FlattenHeadings(toc), minLevel, maxLevel)
+		return htmltpl.HTML(markdown.RenderTableOfContents(tree))
+	}
+
 	funcMap["atomUrl"] = func() string {
-		return generator.FullUrl("atom.xml")
+		return generator.FullUrl(path.Join(generator.renderingLanguage(), "atom.xml"))
 	}
 
 	funcMap["atomLink"] = func() htmltpl.HTML {
 		return htmltpl.HTML(generator.ag.AtomLinks())
 	}
 
+	funcMap["termAtomLink"] = func() htmltpl.HTML {
+		return htmltpl.HTML(generator.ag.TermAtomLink())
+	}
+
 	funcMap["devScripts"] = func() htmltpl.HTML {
 		if generator.Config.DevMode {
 			return htmltpl.HTML(`
@@ -92,11 +144,6 @@ func New(cfg *config.Config, verbose bool) (*Generator, error) {
 		return nil, err
 	}
 
-	generator.hierarchy = NewPageHierarchy(ContentHierarchyOptions{
-		IncludeDrafts: cfg.IncludeDrafts,
-		Verbose:       verbose,
-	})
-
 	funcMap := defineFuncs(generator)
 	templates := template.New(funcMap)
 	err = templates.LoadTemplates(path.Join(srcDir, "templates"))
@@ -104,7 +151,36 @@ func New(cfg *config.Config, verbose bool) (*Generator, error) {
 		return nil, err
 	}
 
+	filenameDates, err := content.NewFilenameDates(cfg.Filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	generator.i18n, err = i18n.Load(path.Join(srcDir, "i18n"), cfg.SiteLanguage())
+	if err != nil {
+		return nil, err
+	}
+	generator.missingTranslations = make(map[string]bool)
+
+	md, err := markdown.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	generator.Tmpl = templates
+	generator.markdown = markdown.WithShortcodes(md, templates)
+	generator.hierarchy = NewPageHierarchy(ContentHierarchyOptions{
+		IncludeDrafts:   cfg.IncludeDrafts,
+		IncludeFuture:   cfg.IncludeFuture,
+		UpdatedFromGit:  cfg.UpdatedFromGit,
+		Verbose:         verbose,
+		Markdown:        generator.markdown,
+		FilenameDates:   filenameDates,
+		WarnBrokenLinks: cfg.DevMode,
+		Languages:       cfg.TranslationLanguages(),
+		Taxonomies:      cfg.Taxonomies,
+		TemplateExists:  templates.TemplateExists,
+	})
 	generator.taxonomyCache = make(map[string]TermTTC)
 
 	generator.ag = &AtomGenerator{
@@ -184,18 +260,38 @@ func (g *Generator) Build(now time.Time) error {
 		}
 	}
 
-	err := g.hierarchy.GatherContent(g.Config.ContentDirectoryAbsolute())
+	// data is loaded first so that shortcodes can use it
+	g.Println("Loading data files...")
+	data, err := content.LoadData(path.Join(g.Config.RootDirectory(), "data"))
+	if err != nil {
+		return err
+	}
+	g.site = SiteContent{Data: data}
+
+	err = g.hierarchy.GatherContent(g.Config.ContentDirectoryAbsolute(), now)
+	if err != nil {
+		return err
+	}
+
+	err = g.buildMenus()
 	if err != nil {
 		return err
 	}
 
 	g.Println("\nBuilding site...")
+	g.outputFiles = make(map[string]string)
+	g.pg.usedTermPages = make(map[*content.WebPage]bool)
 	for _, node := range g.hierarchy.Pages {
+		if node.Implicit {
+			continue
+		}
+
 		err := g.pg.GeneratePage(node.Page, now)
 		if err != nil {
 			return err
 		}
 	}
+	g.pg.WarnUnusedTermPages()
 
 	g.Println("\nCopying static files...")
 	err = g.CopyStaticFiles()
@@ -229,8 +325,13 @@ func (g *Generator) Build(now time.Time) error {
 
 func (g *Generator) CopyStaticFiles() error {
 	for relPath, fullPath := range g.hierarchy.StaticFiles {
+		err := g.claimOutput(relPath, fmt.Sprintf("the static file %s", filepath.ToSlash(relPath)))
+		if err != nil {
+			return err
+		}
+
 		destinationPath := g.OutputPath(relPath)
-		err := os.MkdirAll(filepath.Dir(destinationPath), 0755)
+		err = os.MkdirAll(filepath.Dir(destinationPath), 0755)
 		if err != nil {
 			return err
 		}
@@ -245,6 +346,18 @@ func (g *Generator) CopyStaticFiles() error {
 	return nil
 }
 
+// claimOutput records that owner writes the file at relPath in the output
+// directory. It fails if something else already writes that file.
+func (g *Generator) claimOutput(relPath string, owner string) error {
+	key := filepath.Clean(filepath.FromSlash(relPath))
+	if existing, ok := g.outputFiles[key]; ok {
+		return fmt.Errorf("%s and %s are both written to %s", existing, owner, filepath.ToSlash(key))
+	}
+	g.outputFiles[key] = owner
+
+	return nil
+}
+
 func (g *Generator) shouldRunPostBuild() bool {
 	return g.Config.PostBuildCmd != "" && !g.Config.DevMode
 }
@@ -267,9 +380,17 @@ func (g *Generator) GenerateSitemap() error {
 	// Gather URLs
 	slices.SortStableFunc(g.renderedPaths, compareAlpha)
 	for _, path := range g.renderedPaths {
-		sitemap.Urls = append(sitemap.Urls, &SitemapUrl{
-			Loc: g.FullUrl(path),
-		})
+		sitemapUrl := &SitemapUrl{Loc: g.FullUrl(path)}
+		page := g.hierarchy.GetPage(filepath.FromSlash(strings.Trim(path, "/")))
+		if page != nil && !page.FrontMatter.Updated.IsZero() {
+			sitemapUrl.Lastmod = page.FrontMatter.Updated.Format(time.RFC3339)
+		}
+
+		if page != nil && len(g.Config.TranslationLanguages()) > 0 {
+			sitemap.XmlnsXhtml = "http://www.w3.org/1999/xhtml"
+			sitemapUrl.Alternates = g.sitemapAlternates(page)
+		}
+		sitemap.Urls = append(sitemap.Urls, sitemapUrl)
 	}
 
 	sitemapFilePath := g.OutputPath("sitemap.xml")
@@ -287,70 +408,133 @@ func (g *Generator) GenerateSitemap() error {
 	return sitemapFile.Close()
 }
 
+// sitemapAlternates returns the hreflang links of a page and its translations,
+// or nothing if it has no translations.
+func (g *Generator) sitemapAlternates(page *content.WebPage) []*SitemapAlternate {
+	translations := g.hierarchy.GetTranslations(page)
+	if len(translations) == 0 {
+		return nil
+	}
+
+	alternates := []*SitemapAlternate{}
+	for _, translation := range append([]*content.WebPage{page}, translations...) {
+		alternates = append(alternates, &SitemapAlternate{
+			Rel:      "alternate",
+			Hreflang: g.pg.pageLanguage(translation),
+			Href:     g.FullUrl(translation.RootPath()),
+		})
+	}
+	slices.SortFunc(alternates, func(a, b *SitemapAlternate) int {
+		return cmp.Compare(a.Hreflang, b.Hreflang)
+	})
+
+	return alternates
+}
+
 const DEFAULT_TEMPLATE = "default.html"
 
 func (g *Generator) OutputPath(endPath string) string {
 	return path.Join(g.Config.OutputDirectoryAbsolute(), endPath)
 }
 
-func (g *Generator) ensurePopulatedTaxonomyCache(taxonomy string) TermTTC {
-	if cached, ok := g.taxonomyCache[taxonomy]; ok {
+// ensurePopulatedTaxonomyCache caches the terms of a taxonomy by its key (see
+// taxonomyKey).
+func (g *Generator) ensurePopulatedTaxonomyCache(key string) TermTTC {
+	if cached, ok := g.taxonomyCache[key]; ok {
 		return cached
 	}
 
 	ttcCache := make(TermTTC)
-	g.taxonomyCache[taxonomy] = ttcCache
+	g.taxonomyCache[key] = ttcCache
 
-	mapTerms := g.hierarchy.GetTaxonomyTerms(taxonomy)
-	taxonomyIndexPage := g.hierarchy.GetTaxonomyPage(taxonomy)
+	mapTerms := g.hierarchy.GetTaxonomyTerms(key)
+	taxonomyIndexPage := g.hierarchy.GetTaxonomyPage(key)
+	// the terms of taxonomies that are not in the configuration or content
+	// have no pages
+	if taxonomyIndexPage == nil {
+		return ttcCache
+	}
+	taxonomy := taxonomyIndexPage.TaxonomyType()
+	taxonomyConfig, _ := g.Config.FindTaxonomy(taxonomy)
 
 	for term, pages := range mapTerms {
 		if _, ok := ttcCache[term]; !ok {
 			rootPath := content.RootPath(
-				filepath.ToSlash(path.Join(taxonomyIndexPage.RenderedPath(), dashSpaces(term))),
+				path.Join(filepath.ToSlash(taxonomyIndexPage.RenderedPath()), g.hierarchy.TermSlug(taxonomy, term)),
 			)
 			ttc := &TaxonomyTermContent{
 				Term:      term,
+				Name:      g.hierarchy.TermName(taxonomy, term),
+				Parent:    g.hierarchy.ParentTerm(taxonomy, term),
 				PageCount: len(pages),
 				RootPath:  rootPath,
 				Permalink: g.FullUrl(rootPath),
 			}
+			if g.Config.GenerateFeed && taxonomyConfig.Feed {
+				ttc.FeedUrl = g.FullUrl(g.ag.termFeedFileName(taxonomyIndexPage, term))
+			}
 			ttcCache[term] = ttc
 		}
 	}
 
-	g.taxonomyCache[taxonomy] = ttcCache
+	for _, ttc := range ttcCache {
+		if parent, ok := ttcCache[ttc.Parent]; ok {
+			parent.Children = append(parent.Children, ttc)
+		}
+	}
+	for _, ttc := range ttcCache {
+		slices.SortStableFunc(ttc.Children, compareTerms)
+	}
+
+	g.taxonomyCache[key] = ttcCache
 
 	return ttcCache
 }
 
-func (g *Generator) GetAllTaxonomyTerms(taxonomy string) (termTemplates []*TaxonomyTermContent) {
-	ttcCache := g.ensurePopulatedTaxonomyCache(taxonomy)
+// GetAllTaxonomyTerms returns the terms of a taxonomy by its key (see
+// taxonomyKey).
+func (g *Generator) GetAllTaxonomyTerms(key string) (termTemplates []*TaxonomyTermContent) {
+	ttcCache := g.ensurePopulatedTaxonomyCache(key)
 
 	for _, cached := range ttcCache {
 		termTemplates = append(termTemplates, cached)
 	}
 
-	slices.SortStableFunc(termTemplates, func(a, b *TaxonomyTermContent) int {
-		return cmp.Compare(a.Term, b.Term)
-	})
+	slices.SortStableFunc(termTemplates, compareTerms)
 
 	return termTemplates
 }
 
+// GetTaxonomyTermTree returns the terms at the top of a taxonomy by its key
+// (see taxonomyKey). The terms below them are their Children.
+func (g *Generator) GetTaxonomyTermTree(key string) (termTemplates []*TaxonomyTermContent) {
+	for _, ttc := range g.GetAllTaxonomyTerms(key) {
+		if ttc.Parent == "" {
+			termTemplates = append(termTemplates, ttc)
+		}
+	}
+
+	return termTemplates
+}
+
+func compareTerms(a, b *TaxonomyTermContent) int {
+	return cmp.Compare(a.Term, b.Term)
+}
+
 func (g *Generator) GetTaxonomyTermsForPage(rootPath string, taxonomy string) (termTemplates []*TaxonomyTermContent) {
 	ofPage := g.hierarchy.GetPage(rootPath)
-	ttcCache := g.ensurePopulatedTaxonomyCache(taxonomy)
+	key := taxonomyKey(ofPage.LanguagePrefix(), taxonomy)
+	ttcCache := g.ensurePopulatedTaxonomyCache(key)
 	terms := ofPage.FrontMatter.Taxonomies[taxonomy]
 
 	for _, term := range terms {
-		ttc := ttcCache[term]
-		termTemplates = append(termTemplates, ttc)
+		ttc, ok := ttcCache[g.hierarchy.CanonicalTerm(key, term)]
+		if ok && !slices.Contains(termTemplates, ttc) {
+			termTemplates = append(termTemplates, ttc)
+		}
 	}
 
-	slices.SortStableFunc(termTemplates, func(a, b *TaxonomyTermContent) int {
-		return cmp.Compare(a.Term, b.Term)
-	})
+	slices.SortStableFunc(termTemplates, compareTerms)
 
 	return termTemplates
 }
@@ -366,6 +550,53 @@ func (g *Generator) pathValue(templateData any) string {
 	}
 }
 
+// renderingLanguage returns the language prefix of the page being rendered.
+func (g *Generator) renderingLanguage() string {
+	return g.hierarchy.LanguagePrefix(strings.Trim(g.renderingPath, "/"))
+}
+
+// Data returns the data at a slash-separated path in the data directory, like
+// "team/authors" for data/team/authors.toml. An empty path returns all data.
+func (g *Generator) Data(dataPath string) (any, error) {
+	var value any = g.site.Data
+	for _, part := range strings.Split(strings.Trim(dataPath, "/"), "/") {
+		if part == "" {
+			continue
+		}
+
+		table, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("no data at \"%s\"", dataPath)
+		}
+
+		value, ok = table[part]
+		if !ok {
+			return nil, fmt.Errorf("no data at \"%s\"", dataPath)
+		}
+	}
+
+	return value, nil
+}
+
+// Translate returns the i18n message for key in the language of the page being
+// rendered. Keys missing in that language are reported once.
+func (g *Generator) Translate(key string, count ...int) string {
+	language := cmp.Or(g.renderingLanguage(), g.Config.SiteLanguage())
+	text, ok := g.i18n.Translate(language, key, count...)
+
+	reportKey := language + "/" + key
+	if !g.i18n.Has(language, key) && !g.missingTranslations[reportKey] {
+		g.missingTranslations[reportKey] = true
+		if ok {
+			g.hierarchy.Warnf("missing translation for \"%s\" in %s, using %s", key, language, g.Config.SiteLanguage())
+		} else {
+			g.hierarchy.Warnf("missing translation for \"%s\" in %s", key, language)
+		}
+	}
+
+	return text
+}
+
 func (g *Generator) FullUrl(path string) string {
 	return g.SiteUrlWithTrailingSlash() + strings.TrimLeft(filepath.ToSlash(path), "/")
 }
@@ -409,10 +640,6 @@ func slashPath(path string) string {
 	return tmp
 }
 
-func dashSpaces(str string) string {
-	return strings.ReplaceAll(str, " ", "-")
-}
-
 func isMarkdown(info fs.DirEntry) bool {
 	return filepath.Ext(info.Name()) == ".md"
 }
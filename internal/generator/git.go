@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// gitLastModified returns the time of the last commit of each file under dir,
+// keyed by the file's path relative to dir.
+func gitLastModified(dir string) (map[string]time.Time, error) {
+	// Each commit is a NUL followed by its date and the files it changed, which
+	// are not quoted when they have non-ASCII characters
+	output, err := runGit(dir, "-c", "core.quotePath=false", "log", "--format=%x00%cI", "--name-only", "--relative", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified := make(map[string]time.Time)
+	var commitTime time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if line[0] == 0 {
+			commitTime, err = time.Parse(time.RFC3339, line[1:])
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		file := filepath.FromSlash(line)
+		// the log starts with the latest commit
+		if _, ok := lastModified[file]; !ok {
+			lastModified[file] = commitTime
+		}
+	}
+
+	return lastModified, scanner.Err()
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the git history of %s: %w", dir, err)
+	}
+
+	return output, nil
+}
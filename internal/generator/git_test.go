@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func commitFile(t *testing.T, repo, file, date string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repo, file)), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(repo, file), []byte(date), 0644))
+
+	for _, args := range [][]string{
+		{"add", file},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", file},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date, "GIT_AUTHOR_DATE="+date)
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+	}
+}
+
+func TestGitLastModified(t *testing.T) {
+	repo := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = repo
+	assert.NoError(t, cmd.Run())
+
+	commitFile(t, repo, "content/posts/day-1.md", "2024-02-01T10:00:00Z")
+	commitFile(t, repo, "content/posts/day-2.md", "2024-02-02T10:00:00Z")
+	commitFile(t, repo, "content/posts/day-1.md", "2024-02-05T10:00:00Z")
+	commitFile(t, repo, "content/posts/café.md", "2024-02-03T10:00:00Z")
+	commitFile(t, repo, "templates/default.html", "2024-02-06T10:00:00Z")
+
+	lastModified, err := gitLastModified(filepath.Join(repo, "content"))
+
+	assert.NoError(t, err)
+	assert.Len(t, lastModified, 3)
+	assert.True(t, time.Date(2024, time.February, 5, 10, 0, 0, 0, time.UTC).Equal(
+		lastModified[filepath.Join("posts", "day-1.md")],
+	))
+	assert.True(t, time.Date(2024, time.February, 2, 10, 0, 0, 0, time.UTC).Equal(
+		lastModified[filepath.Join("posts", "day-2.md")],
+	))
+	assert.True(t, time.Date(2024, time.February, 3, 10, 0, 0, 0, time.UTC).Equal(
+		lastModified[filepath.Join("posts", "café.md")],
+	))
+}
+
+func TestGitLastModifiedOutsideARepository(t *testing.T) {
+	_, err := gitLastModified(t.TempDir())
+	assert.ErrorContains(t, err, "unable to read the git history of")
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"encoding/json"
+	htmltpl "html/template"
+)
+
+type jsonLdListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item"`
+}
+
+type jsonLdBreadcrumbList struct {
+	Context         string           `json:"@context"`
+	Type            string           `json:"@type"`
+	ItemListElement []jsonLdListItem `json:"itemListElement"`
+}
+
+// BreadcrumbListJsonLd renders the breadcrumbs as a schema.org BreadcrumbList
+// in a JSON-LD script element.
+func BreadcrumbListJsonLd(crumbs []Breadcrumb) (htmltpl.HTML, error) {
+	list := jsonLdBreadcrumbList{
+		Context:         "https://schema.org",
+		Type:            "BreadcrumbList",
+		ItemListElement: []jsonLdListItem{},
+	}
+	for i, crumb := range crumbs {
+		list.ItemListElement = append(list.ItemListElement, jsonLdListItem{
+			Type:     "ListItem",
+			Position: i + 1,
+			Name:     crumb.Title,
+			Item:     crumb.Permalink,
+		})
+	}
+
+	// json.Marshal escapes <, > and & so the script element can't be closed
+	data, err := json.Marshal(list)
+	if err != nil {
+		return "", err
+	}
+
+	return htmltpl.HTML(`<script type="application/ld+json">` + string(data) + `</script>`), nil
+}
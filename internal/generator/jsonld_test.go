@@ -0,0 +1,23 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreadcrumbListJsonLd(t *testing.T) {
+	html, err := BreadcrumbListJsonLd([]Breadcrumb{
+		{Title: "Home", RootPath: "/", Permalink: "http://example.com/"},
+		{Title: "Q&A </script>", RootPath: "/qa/", Permalink: "http://example.com/qa/"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[`+
+			`{"@type":"ListItem","position":1,"name":"Home","item":"http://example.com/"},`+
+			`{"@type":"ListItem","position":2,"name":"Q\u0026A \u003c/script\u003e","item":"http://example.com/qa/"}]}</script>`,
+		string(html),
+	)
+}
@@ -0,0 +1,187 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"codeberg.org/asartalo/assg/internal/content"
+)
+
+// MenuItem is a link in a site menu.
+type MenuItem struct {
+	Name   string
+	Url    string
+	Weight int
+	// Active is true if the item links to the page being rendered
+	Active   bool
+	Children []MenuItem
+}
+
+type menuNode struct {
+	name     string
+	url      string
+	weight   int
+	children []*menuNode
+	// page is the page that joined the menu, nil for configured entries
+	page *content.WebPage
+}
+
+// inLanguage returns true if the entry is shown in the language. Configured
+// entries are shown in all languages, pages only in theirs.
+func (node *menuNode) inLanguage(language string) bool {
+	return node.page == nil || node.page.LanguagePrefix() == language
+}
+
+type pageMenuEntry struct {
+	page  *content.WebPage
+	menu  string
+	entry content.PageMenu
+}
+
+// buildMenus gathers the menus declared in the configuration and the pages
+// that join them from their front matter.
+func (g *Generator) buildMenus() error {
+	g.Println("Building menus...")
+	g.menus = make(map[string][]*menuNode)
+	for name, entries := range g.Config.Menus {
+		g.menus[name] = menuNodesFromConfig(entries)
+	}
+
+	pending := []pageMenuEntry{}
+	for _, page := range g.hierarchy.SortedPages() {
+		if g.hierarchy.IsImplicit(page) {
+			continue
+		}
+
+		for name, entry := range page.FrontMatter.Menu {
+			pending = append(pending, pageMenuEntry{page: page, menu: name, entry: entry})
+		}
+	}
+
+	// pages can be listed under other pages so they are added once their
+	// parents are
+	for len(pending) > 0 {
+		remaining := []pageMenuEntry{}
+		for _, pme := range pending {
+			node := &menuNode{
+				name:   cmp.Or(pme.entry.Name, pme.page.FrontMatter.Title),
+				url:    pme.page.RootPath(),
+				weight: pme.entry.Weight,
+				page:   pme.page,
+			}
+
+			language := pme.page.LanguagePrefix()
+			if pme.entry.Parent == "" {
+				g.menus[pme.menu] = append(g.menus[pme.menu], node)
+			} else if parent := findMenuNode(g.menus[pme.menu], pme.entry.Parent, language); parent != nil {
+				parent.children = append(parent.children, node)
+			} else {
+				remaining = append(remaining, pme)
+			}
+		}
+
+		if len(remaining) == len(pending) {
+			pme := remaining[0]
+			return fmt.Errorf(
+				"%s: the menu \"%s\" has no entry named \"%s\"",
+				pme.page.MarkdownPath,
+				pme.menu,
+				pme.entry.Parent,
+			)
+		}
+		pending = remaining
+	}
+
+	for _, nodes := range g.menus {
+		sortMenuNodes(nodes)
+	}
+
+	return nil
+}
+
+func menuNodesFromConfig(entries []config.MenuEntry) []*menuNode {
+	nodes := []*menuNode{}
+	for _, entry := range entries {
+		nodes = append(nodes, &menuNode{
+			name:     entry.Name,
+			url:      entry.Url,
+			weight:   entry.Weight,
+			children: menuNodesFromConfig(entry.Children),
+		})
+	}
+
+	return nodes
+}
+
+// findMenuNode finds an entry by name among the entries shown in the language.
+func findMenuNode(nodes []*menuNode, name string, language string) *menuNode {
+	for _, node := range nodes {
+		if !node.inLanguage(language) {
+			continue
+		}
+
+		if node.name == name {
+			return node
+		}
+
+		if found := findMenuNode(node.children, name, language); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// sortMenuNodes orders the items by weight, then by name.
+func sortMenuNodes(nodes []*menuNode) {
+	slices.SortStableFunc(nodes, func(a, b *menuNode) int {
+		return cmp.Or(cmp.Compare(a.weight, b.weight), cmp.Compare(a.name, b.name))
+	})
+
+	for _, node := range nodes {
+		sortMenuNodes(node.children)
+	}
+}
+
+// Menu returns the items of a menu for the page being rendered, in its
+// language.
+func (g *Generator) Menu(name string) []MenuItem {
+	return g.menuItems(g.menus[name], g.renderingLanguage())
+}
+
+func (g *Generator) menuItems(nodes []*menuNode, language string) []MenuItem {
+	items := []MenuItem{}
+	for _, node := range nodes {
+		if !node.inLanguage(language) {
+			continue
+		}
+
+		items = append(items, MenuItem{
+			Name:     node.name,
+			Url:      node.url,
+			Weight:   node.weight,
+			Active:   g.menuRootPath(node.url) == g.renderingPath,
+			Children: g.menuItems(node.children, language),
+		})
+	}
+
+	return items
+}
+
+// menuRootPath returns the root path of a menu item's URL so it can be compared
+// with the root path of a page.
+func (g *Generator) menuRootPath(url string) string {
+	if rest, ok := strings.CutPrefix(url, g.SiteUrlWithTrailingSlash()); ok {
+		url = "/" + rest
+	}
+
+	if strings.HasPrefix(url, "/") && !strings.HasSuffix(url, "/") && path.Ext(url) == "" {
+		url += "/"
+	}
+
+	return url
+}
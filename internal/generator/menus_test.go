@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMenuRootPath(t *testing.T) {
+	g := &Generator{Config: &config.Config{BaseURL: "http://example.com/"}}
+
+	assert.Equal(t, "/", g.menuRootPath("/"))
+	assert.Equal(t, "/about/", g.menuRootPath("/about"))
+	assert.Equal(t, "/about/", g.menuRootPath("http://example.com/about/"))
+	assert.Equal(t, "/atom.xml", g.menuRootPath("/atom.xml"))
+	assert.Equal(t, "https://go.dev", g.menuRootPath("https://go.dev"))
+}
+
+func TestSortMenuNodes(t *testing.T) {
+	nodes := []*menuNode{
+		{name: "B", weight: 2},
+		{name: "C", weight: 1, children: []*menuNode{{name: "Z"}, {name: "Y"}}},
+		{name: "A", weight: 2},
+	}
+	sortMenuNodes(nodes)
+
+	assert.Equal(t, "C", nodes[0].name)
+	assert.Equal(t, "A", nodes[1].name)
+	assert.Equal(t, "B", nodes[2].name)
+	assert.Equal(t, "Y", nodes[0].children[0].name)
+	assert.Same(t, nodes[0].children[1], findMenuNode(nodes, "Z", ""))
+}
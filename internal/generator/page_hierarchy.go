@@ -2,51 +2,110 @@ package generator
 
 import (
 	"cmp"
+	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 
+	"codeberg.org/asartalo/assg/internal/config"
 	"codeberg.org/asartalo/assg/internal/content"
+	"github.com/yuin/goldmark"
 )
 
 type ContentNode struct {
 	Page   *content.WebPage
 	Parent string
+	// Implicit is true for a directory without a page under a section. It
+	// groups the pages in it but is not rendered.
+	Implicit bool
 }
 
 type TermMap map[string][]*content.WebPage
 
 type ContentHierarchy struct {
-	Pages         map[string]*ContentNode
-	Taxonomies    map[string]TermMap
-	TaxonomyPage  map[string]*content.WebPage
+	Pages      map[string]*ContentNode
+	Taxonomies map[string]TermMap
+	// termSlugs maps the slugs of the terms of each taxonomy key to the term
+	termSlugs map[string]map[string]string
+	// canonicalTerms maps the terms of pages of each taxonomy key to the term
+	// they are listed under
+	canonicalTerms map[string]map[string]string
+	taxonomies     config.TaxonomyConfigs
+	// templateExists tells if the site has a template
+	templateExists func(name string) bool
+	TaxonomyPage   map[string]*content.WebPage
+	// termPages holds the Markdown content of taxonomy terms by their path,
+	// like tags/food for content/tags/food.md
+	termPages     map[string]*content.WebPage
 	childrenCache map[string][]*content.WebPage
 	StaticFiles   map[string]string
+	// unpublished maps the source paths of pages left out to the reason
+	unpublished   map[string]string
 	includeDrafts bool
-	verbose       bool
+	includeFuture bool
+	now           time.Time
+	// lastModified holds the git commit times of the content files
+	lastModified   map[string]time.Time
+	updatedFromGit bool
+	verbose        bool
+	markdown       goldmark.Markdown
+	filenameDates  *content.FilenameDates
+	// warnBrokenLinks reports links to missing pages without failing
+	warnBrokenLinks bool
+	// languages are the languages pages are translated to
+	languages []string
+	// translations groups the pages by their translation key
+	translations map[string][]*content.WebPage
 }
 
 type ContentHierarchyOptions struct {
-	IncludeDrafts bool
-	Verbose       bool
+	IncludeDrafts   bool
+	IncludeFuture   bool
+	UpdatedFromGit  bool
+	Verbose         bool
+	Markdown        goldmark.Markdown
+	FilenameDates   *content.FilenameDates
+	WarnBrokenLinks bool
+	Languages       []string
+	Taxonomies      config.TaxonomyConfigs
+	// TemplateExists tells if the site has a template, see addTaxonomyPages
+	TemplateExists func(name string) bool
 }
 
 func NewPageHierarchy(options ContentHierarchyOptions) *ContentHierarchy {
 	return &ContentHierarchy{
-		Pages:         make(map[string]*ContentNode),
-		TaxonomyPage:  make(map[string]*content.WebPage),
-		StaticFiles:   make(map[string]string),
-		verbose:       options.Verbose,
-		includeDrafts: options.IncludeDrafts,
+		Pages:           make(map[string]*ContentNode),
+		TaxonomyPage:    make(map[string]*content.WebPage),
+		termPages:       make(map[string]*content.WebPage),
+		StaticFiles:     make(map[string]string),
+		unpublished:     make(map[string]string),
+		verbose:         options.Verbose,
+		includeDrafts:   options.IncludeDrafts,
+		includeFuture:   options.IncludeFuture,
+		updatedFromGit:  options.UpdatedFromGit,
+		markdown:        options.Markdown,
+		filenameDates:   options.FilenameDates,
+		warnBrokenLinks: options.WarnBrokenLinks,
+		languages:       options.Languages,
+		translations:    make(map[string][]*content.WebPage),
+		taxonomies:      options.Taxonomies,
+		templateExists:  options.TemplateExists,
 	}
 }
 
 func (ph *ContentHierarchy) Clear(args ...interface{}) {
 	ph.Pages = make(map[string]*ContentNode)
 	ph.TaxonomyPage = make(map[string]*content.WebPage)
+	ph.termPages = make(map[string]*content.WebPage)
 	ph.StaticFiles = make(map[string]string)
+	ph.unpublished = make(map[string]string)
+	ph.translations = make(map[string][]*content.WebPage)
 }
 
 func (ph *ContentHierarchy) Println(args ...interface{}) {
@@ -61,33 +120,39 @@ func (ph *ContentHierarchy) Printf(format string, args ...interface{}) {
 	}
 }
 
+// Warnf writes a warning to stderr, whether or not the build is verbose.
+func (ph *ContentHierarchy) Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
+
 func (ph *ContentHierarchy) AddPage(page *content.WebPage) {
 	ph.Println("Adding page:", page.RenderedPath())
-	taxonomies := page.FrontMatter.Taxonomies
-	if ph.Taxonomies == nil {
-		ph.Taxonomies = make(map[string]TermMap)
-	}
-
-	for taxonomy, terms := range taxonomies {
-		for _, term := range terms {
-			if ph.Taxonomies[taxonomy] == nil {
-				ph.Taxonomies[taxonomy] = make(TermMap)
-			}
-			ph.Taxonomies[taxonomy][term] = append(ph.Taxonomies[taxonomy][term], page)
-		}
-	}
 	ph.Pages[page.RenderedPath()] = &ContentNode{
 		Page: page,
 	}
 
+	translationKey := page.TranslationKey()
+	ph.translations[translationKey] = append(ph.translations[translationKey], page)
+
 	if page.IsTaxonomy() {
 		ph.Printf("  It's a taxonomy page: %s\n", page.TaxonomyType())
-		ph.TaxonomyPage[page.TaxonomyType()] = page
+		ph.TaxonomyPage[taxonomyKey(page.LanguagePrefix(), page.TaxonomyType())] = page
 	}
 }
 
-func (ph *ContentHierarchy) GatherContent(contentDir string) error {
+// GatherContent parses the content directory. Pages dated after now or that
+// expired by then are left out.
+func (ph *ContentHierarchy) GatherContent(contentDir string, now time.Time) error {
 	ph.Clear()
+	ph.now = now
+
+	if ph.updatedFromGit {
+		lastModified, err := gitLastModified(contentDir)
+		if err != nil {
+			return err
+		}
+		ph.lastModified = lastModified
+	}
 	err := filepath.WalkDir(contentDir, func(contentPath string, info fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -119,8 +184,26 @@ func (ph *ContentHierarchy) GatherContent(contentDir string) error {
 	if err != nil {
 		return err
 	}
+	ph.addTaxonomyPages()
+
+	if err := ph.applyPaths(); err != nil {
+		return err
+	}
+
+	if err := ph.validateSorting(); err != nil {
+		return err
+	}
+
+	if err := ph.collectTaxonomies(); err != nil {
+		return err
+	}
+	ph.collectResources()
 	ph.Retree()
 
+	if err := ph.resolveReferences(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -130,23 +213,232 @@ func (ph *ContentHierarchy) handleMarkdownFile(dPath string, relPath string) err
 		return err
 	}
 
-	page, err := content.ParsePage(relPath, fileContent)
+	language := content.FileLanguage(relPath, ph.languages)
+	page, err := content.ParseTranslation(ph.markdown, relPath, language, fileContent)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to parse %s: %w", relPath, err)
 	}
 
-	if !page.IsDraft() || ph.includeDrafts {
+	if ph.filenameDates != nil {
+		if err := ph.filenameDates.Apply(page); err != nil {
+			return err
+		}
+	}
+
+	ph.setUpdatedFromGit(page, relPath)
+
+	sourcePath := filepath.ToSlash(relPath)
+	switch {
+	case page.IsDraft() && !ph.includeDrafts:
+		ph.unpublished[sourcePath] = "draft"
+	case page.IsScheduled(ph.now) && !ph.includeFuture:
+		ph.Println("  Scheduled for", page.FrontMatter.Date)
+		ph.unpublished[sourcePath] = "scheduled"
+	case page.IsExpired(ph.now):
+		ph.Println("  Expired on", page.FrontMatter.ExpiryDate)
+		ph.unpublished[sourcePath] = "expired"
+	default:
 		ph.AddPage(page)
 	}
 
 	return nil
 }
 
+// setUpdatedFromGit sets the updated date of a page without one to the time of
+// the last commit of its file, if that is after the page's date.
+func (ph *ContentHierarchy) setUpdatedFromGit(page *content.WebPage, relPath string) {
+	if ph.lastModified == nil || !page.FrontMatter.Updated.IsZero() {
+		return
+	}
+
+	if committed, ok := ph.lastModified[relPath]; ok && committed.After(page.FrontMatter.Date) {
+		ph.Println("  Last commit:", committed)
+		page.FrontMatter.Updated = committed
+	}
+}
+
 func (ph *ContentHierarchy) AddStaticFile(relPath, fullPath string) {
 	ph.Println("Adding static file:", relPath)
 	ph.StaticFiles[relPath] = fullPath
 }
 
+// applyPaths sets the final path of each page from the final path of the
+// page's parent directory, so a section's slug also moves its pages. The pages
+// are then keyed by their final paths.
+func (ph *ContentHierarchy) applyPaths() error {
+	bySource := make(map[string]*content.WebPage)
+	sourcePaths := make([]string, 0, len(ph.Pages))
+	for _, node := range ph.Pages {
+		sourcePath := node.Page.SourcePath()
+		bySource[sourcePath] = node.Page
+		sourcePaths = append(sourcePaths, sourcePath)
+	}
+
+	// parents come before their children
+	slices.SortFunc(sourcePaths, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(strings.Count(a, string(filepath.Separator)), strings.Count(b, string(filepath.Separator))),
+			cmp.Compare(a, b),
+		)
+	})
+
+	var finalDir func(dir string) string
+	finalDir = func(dir string) string {
+		if dir == "." || dir == "" {
+			return ""
+		}
+
+		if page, ok := bySource[dir]; ok {
+			return page.RenderedPath()
+		}
+
+		return filepath.Join(finalDir(filepath.Dir(dir)), filepath.Base(dir))
+	}
+
+	pages := make(map[string]*ContentNode)
+	for _, sourcePath := range sourcePaths {
+		page := bySource[sourcePath]
+		page.SetRenderedPath(page.PathUnder(finalDir(filepath.Dir(sourcePath))))
+
+		renderedPath := page.RenderedPath()
+		if existing, ok := pages[renderedPath]; ok {
+			return fmt.Errorf(
+				"%s and %s are both rendered at \"%s\"",
+				existing.Page.MarkdownPath,
+				page.MarkdownPath,
+				page.RootPath(),
+			)
+		}
+
+		if renderedPath != sourcePath {
+			ph.Printf("  %s is rendered at %s\n", page.MarkdownPath, page.RootPath())
+		}
+		pages[renderedPath] = &ContentNode{Page: page}
+	}
+	ph.Pages = pages
+
+	return ph.validateAliases()
+}
+
+// validateAliases checks that the aliases of each page are paths inside the
+// site that are not taken by a page or by another alias.
+func (ph *ContentHierarchy) validateAliases() error {
+	aliases := make(map[string]*content.WebPage)
+	for _, renderedPath := range slices.Sorted(maps.Keys(ph.Pages)) {
+		page := ph.Pages[renderedPath].Page
+		for _, alias := range page.FrontMatter.Aliases {
+			aliasPath := strings.Trim(alias, "/")
+			if aliasPath == "" {
+				return fmt.Errorf("%s: the alias \"%s\" is empty", page.MarkdownPath, alias)
+			}
+
+			if slices.Contains(strings.Split(aliasPath, "/"), "..") {
+				return fmt.Errorf("%s: the alias \"%s\" is outside the site", page.MarkdownPath, alias)
+			}
+
+			key := filepath.FromSlash(aliasPath)
+			if existing, ok := ph.Pages[key]; ok {
+				return fmt.Errorf(
+					"%s: the alias \"%s\" is the path of %s",
+					page.MarkdownPath,
+					alias,
+					existing.Page.MarkdownPath,
+				)
+			}
+
+			if existing, ok := aliases[key]; ok {
+				return fmt.Errorf(
+					"%s and %s both have the alias \"%s\"",
+					existing.MarkdownPath,
+					page.MarkdownPath,
+					content.RootPath(aliasPath),
+				)
+			}
+			aliases[key] = page
+		}
+	}
+
+	return nil
+}
+
+// collectResources assigns the static files in a page bundle's directory to
+// the bundle's page and its translations. Files in a nested bundle belong to
+// the nested one. The files of a bundle rendered somewhere else are moved
+// along with its page in the default language.
+func (ph *ContentHierarchy) collectResources() {
+	// translations of a bundle share its directory, e.g. posts/day-1 for
+	// posts/day-1/index.md and posts/day-1/index.fr.md
+	bundles := make(map[string][]*content.WebPage)
+	for _, node := range ph.Pages {
+		if node.Page.IsBundle() && !node.Page.IsIndex() {
+			node.Page.Resources = nil
+			dir := filepath.Dir(node.Page.MarkdownPath)
+			bundles[dir] = append(bundles[dir], node.Page)
+		}
+	}
+
+	for relPath := range ph.StaticFiles {
+		for dir := filepath.Dir(relPath); dir != "."; dir = filepath.Dir(dir) {
+			pages, ok := bundles[dir]
+			if !ok {
+				continue
+			}
+
+			resource, err := filepath.Rel(dir, relPath)
+			if err == nil {
+				for _, page := range pages {
+					ph.Printf("  Resource of %s: %s\n", page.MarkdownPath, resource)
+					page.Resources = append(page.Resources, filepath.ToSlash(resource))
+				}
+			}
+			break
+		}
+	}
+
+	for dir, pages := range bundles {
+		filesDir := dir
+		for _, page := range pages {
+			slices.Sort(page.Resources)
+			if page.LanguagePrefix() == "" {
+				filesDir = page.RenderedPath()
+			}
+		}
+
+		for _, page := range pages {
+			page.ResourcesPath = content.RootPath(filepath.ToSlash(filesDir))
+		}
+
+		if filesDir == dir {
+			continue
+		}
+
+		ph.moveResources(pages[0].Resources, dir, filesDir)
+		for _, page := range pages {
+			relocateLinks(page, dir, filesDir)
+		}
+	}
+}
+
+func (ph *ContentHierarchy) moveResources(resources []string, fromDir, toDir string) {
+	for _, resource := range resources {
+		from := filepath.Join(fromDir, filepath.FromSlash(resource))
+		to := filepath.Join(toDir, filepath.FromSlash(resource))
+		ph.StaticFiles[to] = ph.StaticFiles[from]
+		delete(ph.StaticFiles, from)
+	}
+}
+
+// relocateLinks points the relative links of a bundle's page, which were
+// resolved against the bundle's directory, to where its files were moved.
+func relocateLinks(page *content.WebPage, fromDir, toDir string) {
+	oldRoot := "=\"" + content.RootPath(filepath.ToSlash(fromDir))
+	newRoot := "=\"" + content.RootPath(filepath.ToSlash(toDir))
+	relocated := strings.ReplaceAll(page.Content.String(), oldRoot, newRoot)
+	page.Content.Reset()
+	page.Content.WriteString(relocated)
+	page.Excerpt = strings.ReplaceAll(page.Excerpt, oldRoot, newRoot)
+}
+
 func (ph *ContentHierarchy) SortedPages() []*content.WebPage {
 	pages := make([]*content.WebPage, 0, len(ph.Pages))
 	for _, node := range ph.Pages {
@@ -157,21 +449,359 @@ func (ph *ContentHierarchy) SortedPages() []*content.WebPage {
 	return pages
 }
 
-func (ph *ContentHierarchy) GetTaxonomyTerms(taxonomy string) TermMap {
-	return ph.Taxonomies[taxonomy]
+// The templates of the pages of taxonomies without a Markdown page, see
+// addTaxonomyPages.
+const (
+	TAXONOMY_TEMPLATE      = "taxonomy.html"
+	TAXONOMY_TERM_TEMPLATE = "taxonomy_term.html"
+)
+
+// addTaxonomyPages adds a page for each taxonomy in the configuration that has
+// no Markdown page. It is added in the default language, and in each language
+// with pages that have terms of the taxonomy. Sites without the taxonomy
+// templates get no pages, as they have nothing to render them with.
+func (ph *ContentHierarchy) addTaxonomyPages() {
+	if ph.templateExists == nil ||
+		!ph.templateExists(TAXONOMY_TEMPLATE) ||
+		!ph.templateExists(TAXONOMY_TERM_TEMPLATE) {
+		ph.Printf("No %s and %s templates for the taxonomy pages\n", TAXONOMY_TEMPLATE, TAXONOMY_TERM_TEMPLATE)
+		return
+	}
+
+	for _, taxonomy := range ph.taxonomies {
+		for _, language := range ph.taxonomyLanguages(taxonomy.Name) {
+			if _, ok := ph.TaxonomyPage[taxonomyKey(language, taxonomy.Name)]; ok {
+				continue
+			}
+
+			markdownPath := fmt.Sprintf("%s.md", taxonomy.Name)
+			if language != "" {
+				markdownPath = fmt.Sprintf("%s.%s.md", taxonomy.Name, language)
+			}
+			page := content.NewTranslationPage(ph.markdown, markdownPath, language, content.FrontMatter{
+				Title:    titleCase(taxonomy.Name),
+				Language: language,
+				Template: TAXONOMY_TEMPLATE,
+				Index: content.IndexFields{
+					Taxonomy:     taxonomy.Name,
+					PageTemplate: TAXONOMY_TERM_TEMPLATE,
+					PaginateBy:   taxonomy.PaginateBy,
+				},
+			})
+
+			if existing, ok := ph.Pages[page.RenderedPath()]; ok {
+				ph.Printf(
+					"  %s is not the page of the taxonomy \"%s\" as it has no `taxonomy` in its [index]\n",
+					existing.Page.MarkdownPath,
+					taxonomy.Name,
+				)
+				continue
+			}
+
+			ph.Println("Adding the page of the taxonomy:", taxonomy.Name)
+			ph.AddPage(page)
+		}
+	}
+}
+
+// taxonomyLanguages returns the language prefixes of the pages with terms of
+// the taxonomy, and "" for the default language.
+func (ph *ContentHierarchy) taxonomyLanguages(taxonomy string) []string {
+	languages := []string{""}
+	for _, language := range ph.languages {
+		for _, node := range ph.Pages {
+			if node.Page.LanguagePrefix() == language && len(node.Page.FrontMatter.Taxonomies[taxonomy]) > 0 {
+				languages = append(languages, language)
+				break
+			}
+		}
+	}
+
+	return languages
+}
+
+// collectTaxonomies groups the pages by the terms of their taxonomies. Terms
+// are replaced by their aliases, and terms with the same slug are the same
+// term, named as in the first page that has it. Different terms with the same
+// slug are errors.
+func (ph *ContentHierarchy) collectTaxonomies() error {
+	ph.Taxonomies = make(map[string]TermMap)
+	ph.termSlugs = make(map[string]map[string]string)
+	ph.canonicalTerms = make(map[string]map[string]string)
+
+	errs := []error{}
+	for _, path := range slices.Sorted(maps.Keys(ph.Pages)) {
+		page := ph.Pages[path].Page
+		for _, taxonomy := range slices.Sorted(maps.Keys(page.FrontMatter.Taxonomies)) {
+			key := taxonomyKey(page.LanguagePrefix(), taxonomy)
+			for _, term := range page.FrontMatter.Taxonomies[taxonomy] {
+				terms, err := ph.addTerms(key, taxonomy, term)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", page.MarkdownPath, err))
+					continue
+				}
+
+				if ph.canonicalTerms[key] == nil {
+					ph.canonicalTerms[key] = make(map[string]string)
+				}
+				ph.canonicalTerms[key][term] = terms[len(terms)-1]
+
+				if ph.Taxonomies[key] == nil {
+					ph.Taxonomies[key] = make(TermMap)
+				}
+				for _, term := range terms {
+					if !slices.Contains(ph.Taxonomies[key][term], page) {
+						ph.Taxonomies[key][term] = append(ph.Taxonomies[key][term], page)
+					}
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// addTerms returns the terms a page's term is listed under. In a hierarchical
+// taxonomy these are the term and the terms above it, like "tech" and
+// "tech/go" for "tech/go", starting from the top.
+func (ph *ContentHierarchy) addTerms(key, taxonomy, term string) ([]string, error) {
+	taxonomyConfig, _ := ph.taxonomies.Find(taxonomy)
+	if !taxonomyConfig.Hierarchical {
+		term, err := ph.addTerm(key, taxonomy, term)
+		return []string{term}, err
+	}
+
+	terms := []string{}
+	parent := ""
+	for _, name := range strings.Split(ph.aliasedTerm(taxonomy, term), "/") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		term, err := ph.addTerm(key, taxonomy, path.Join(parent, name))
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		parent = term
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("the term \"%s\" of %s has no letters or digits for its URL", term, taxonomy)
+	}
+
+	return terms, nil
+}
+
+// addTerm returns the term a page's term is listed under, adding it if it is
+// the first with its slug.
+func (ph *ContentHierarchy) addTerm(key, taxonomy, term string) (string, error) {
+	term = ph.aliasedTerm(taxonomy, term)
+	slug := ph.TermSlug(taxonomy, term)
+	if slug == "" {
+		return "", fmt.Errorf("the term \"%s\" of %s has no letters or digits for its URL", term, taxonomy)
+	}
+
+	if ph.termSlugs[key] == nil {
+		ph.termSlugs[key] = make(map[string]string)
+	}
+
+	existing, ok := ph.termSlugs[key][slug]
+	if !ok {
+		ph.termSlugs[key][slug] = term
+		return term, nil
+	}
+
+	taxonomyConfig, _ := ph.taxonomies.Find(taxonomy)
+	if existing == term || (!taxonomyConfig.CaseSensitive && strings.EqualFold(existing, term)) {
+		return existing, nil
+	}
+
+	return "", fmt.Errorf(
+		"the terms \"%s\" and \"%s\" of %s have the same slug \"%s\"",
+		existing,
+		term,
+		taxonomy,
+		slug,
+	)
+}
+
+// aliasedTerm returns the term an alias in the taxonomy's configuration
+// replaces the term with. Aliases match terms with the same slug.
+func (ph *ContentHierarchy) aliasedTerm(taxonomy, term string) string {
+	term = strings.TrimSpace(term)
+	taxonomyConfig, _ := ph.taxonomies.Find(taxonomy)
+	if target, ok := taxonomyConfig.Aliases[term]; ok {
+		return target
+	}
+
+	slug := content.Slugify(term)
+	for _, alias := range slices.Sorted(maps.Keys(taxonomyConfig.Aliases)) {
+		if content.Slugify(alias) == slug {
+			return taxonomyConfig.Aliases[alias]
+		}
+	}
+
+	return term
+}
+
+// CanonicalTerm returns the term a page's term is listed under in the
+// taxonomy with the key (see taxonomyKey).
+func (ph *ContentHierarchy) CanonicalTerm(key, term string) string {
+	if canonical, ok := ph.canonicalTerms[key][term]; ok {
+		return canonical
+	}
+
+	return term
+}
+
+// TermSlug returns the path of a term's page under its taxonomy's page. The
+// slug of a term in a hierarchical taxonomy is the path of the slugs of its
+// parts, like "tech/go". It is empty if a part has no letters or digits.
+func (ph *ContentHierarchy) TermSlug(taxonomy, term string) string {
+	taxonomyConfig, _ := ph.taxonomies.Find(taxonomy)
+	if !taxonomyConfig.Hierarchical {
+		return content.Slugify(term)
+	}
+
+	slugs := []string{}
+	for _, name := range strings.Split(term, "/") {
+		slug := content.Slugify(name)
+		if slug == "" {
+			return ""
+		}
+		slugs = append(slugs, slug)
+	}
+
+	return strings.Join(slugs, "/")
+}
+
+// TermName returns the last part of a term of a hierarchical taxonomy, like
+// "go" for "tech/go", or the term itself in other taxonomies.
+func (ph *ContentHierarchy) TermName(taxonomy, term string) string {
+	taxonomyConfig, _ := ph.taxonomies.Find(taxonomy)
+	if !taxonomyConfig.Hierarchical {
+		return term
+	}
+
+	return path.Base(term)
+}
+
+// ParentTerm returns the term above a term of a hierarchical taxonomy, or ""
+// if it is at the top or the taxonomy is not hierarchical.
+func (ph *ContentHierarchy) ParentTerm(taxonomy, term string) string {
+	taxonomyConfig, _ := ph.taxonomies.Find(taxonomy)
+	if !taxonomyConfig.Hierarchical || !strings.Contains(term, "/") {
+		return ""
+	}
+
+	return path.Dir(term)
 }
 
-func (ph *ContentHierarchy) GetTaxonomyPage(taxonomy string) *content.WebPage {
-	return ph.TaxonomyPage[taxonomy]
+// taxonomyKey returns the key of a taxonomy's terms and page in a language. The
+// taxonomies of translations are kept apart, e.g. "fr/tags".
+func taxonomyKey(languagePrefix, taxonomy string) string {
+	return path.Join(languagePrefix, taxonomy)
+}
+
+// GetTaxonomyTerms returns the terms of a taxonomy by its key (see taxonomyKey).
+func (ph *ContentHierarchy) GetTaxonomyTerms(key string) TermMap {
+	return ph.Taxonomies[key]
+}
+
+// GetTaxonomyPage returns the page of a taxonomy by its key (see taxonomyKey).
+func (ph *ContentHierarchy) GetTaxonomyPage(key string) *content.WebPage {
+	return ph.TaxonomyPage[key]
+}
+
+// LanguagePrefix returns the language a path starts with, or "" for paths in
+// the default language.
+func (ph *ContentHierarchy) LanguagePrefix(pagePath string) string {
+	first, _, _ := strings.Cut(filepath.ToSlash(pagePath), "/")
+	if slices.Contains(ph.languages, first) {
+		return first
+	}
+
+	return ""
+}
+
+// GetTranslations returns the other translations of a page, the one in the
+// default language first.
+func (ph *ContentHierarchy) GetTranslations(page *content.WebPage) []*content.WebPage {
+	translations := []*content.WebPage{}
+	for _, translation := range ph.translations[page.TranslationKey()] {
+		if translation != page {
+			translations = append(translations, translation)
+		}
+	}
+
+	slices.SortFunc(translations, func(a, b *content.WebPage) int {
+		return cmp.Compare(a.LanguagePrefix(), b.LanguagePrefix())
+	})
+
+	return translations
 }
 
 var comparePageByDate = func(a, b *content.WebPage) int {
-	return cmp.Compare(b.DateUnixEpoch(), a.DateUnixEpoch())
+	return cmp.Or(
+		cmp.Compare(b.DateUnixEpoch(), a.DateUnixEpoch()),
+		cmp.Compare(a.MarkdownPath, b.MarkdownPath),
+	)
+}
+
+// ChildComparator returns the order of the pages under an index page, set by
+// its sort_by and sort_reverse.
+func (ph *ContentHierarchy) ChildComparator(page *content.WebPage) func(a, b *content.WebPage) int {
+	index := page.FrontMatter.Index
+	compare, err := content.PageComparator(index.SortBy, index.SortReverse)
+	if err != nil {
+		return comparePageByDate
+	}
+
+	return compare
+}
+
+func (ph *ContentHierarchy) validateSorting() error {
+	for _, node := range ph.Pages {
+		page := node.Page
+		if !page.IsIndex() {
+			continue
+		}
+
+		index := page.FrontMatter.Index
+		if index.SortBy == "" && page.IsTaxonomy() {
+			continue
+		}
+
+		if _, err := content.PageComparator(index.SortBy, index.SortReverse); err != nil {
+			return fmt.Errorf("%s: %w", page.MarkdownPath, err)
+		}
+	}
+
+	return nil
 }
 
 func (ph *ContentHierarchy) Retree() {
 	ph.Println("Recalculating hierarchies...")
 
+	for path, node := range ph.Pages {
+		if node.Implicit {
+			delete(ph.Pages, path)
+		}
+	}
+	ph.childrenCache = nil
+	ph.separateTermPages()
+
+	paths := make([]string, 0, len(ph.Pages))
+	for path := range ph.Pages {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		ph.addImplicitSections(path)
+	}
+
 	for path, node := range ph.Pages {
 		ph.Println("  Checking:", path)
 		possibleParent := filepath.Dir(path)
@@ -186,6 +816,8 @@ func (ph *ContentHierarchy) Retree() {
 
 		node.Parent = parent
 	}
+	ph.dateImplicitSections()
+
 	for taxonomy, termMap := range ph.Taxonomies {
 		for term, pages := range termMap {
 			slices.SortStableFunc(pages, comparePageByDate)
@@ -194,6 +826,112 @@ func (ph *ContentHierarchy) Retree() {
 	}
 }
 
+// separateTermPages takes the pages under the directory of a taxonomy page out
+// of the hierarchy. They are the content of the term pages of the same slug,
+// see GetTermPage.
+func (ph *ContentHierarchy) separateTermPages() {
+	for path, node := range ph.Pages {
+		if node.Page.IsIndex() || !ph.underTaxonomyPage(path) {
+			continue
+		}
+
+		ph.Println("  Term page:", path)
+		ph.termPages[path] = node.Page
+		delete(ph.Pages, path)
+	}
+}
+
+func (ph *ContentHierarchy) underTaxonomyPage(path string) bool {
+	for dir := filepath.Dir(path); dir != "."; dir = filepath.Dir(dir) {
+		node, ok := ph.Pages[dir]
+		if !ok {
+			continue
+		}
+
+		// terms of hierarchical taxonomies can have pages above them
+		if node.Page.IsTaxonomy() || node.Page.IsIndex() {
+			return node.Page.IsTaxonomy()
+		}
+	}
+
+	return false
+}
+
+// GetTermPage returns the Markdown content of a term of the taxonomy page, or
+// nil if it has none.
+func (ph *ContentHierarchy) GetTermPage(taxonomyPage *content.WebPage, term string) *content.WebPage {
+	slug := ph.TermSlug(taxonomyPage.TaxonomyType(), term)
+	return ph.termPages[filepath.Join(taxonomyPage.RenderedPath(), filepath.FromSlash(slug))]
+}
+
+// addImplicitSections adds the sections missing between a page and the closest
+// section above it, like posts/2024 for posts/2024/jan.md when there is no
+// posts/2024.md. They have the index settings of that section.
+func (ph *ContentHierarchy) addImplicitSections(path string) {
+	missing := []string{}
+	dir := filepath.Dir(path)
+	for ; dir != "."; dir = filepath.Dir(dir) {
+		if _, ok := ph.Pages[dir]; ok {
+			break
+		}
+		missing = append(missing, dir)
+	}
+
+	if dir == "." || len(missing) == 0 {
+		return
+	}
+
+	section := ph.Pages[dir].Page
+	if !section.IsIndex() || section.IsTaxonomy() {
+		return
+	}
+
+	for _, sectionPath := range missing {
+		ph.Println("  Implicit section:", sectionPath)
+		page := content.NewPage(ph.markdown, sectionPath+".md", content.FrontMatter{
+			Title:    filepath.Base(sectionPath),
+			Template: section.FrontMatter.Template,
+			Index:    section.FrontMatter.Index,
+		})
+		page.SetRenderedPath(sectionPath)
+		ph.Pages[sectionPath] = &ContentNode{Page: page, Implicit: true}
+	}
+}
+
+// dateImplicitSections dates each implicit section with its newest page.
+func (ph *ContentHierarchy) dateImplicitSections() {
+	for _, node := range ph.Pages {
+		if node.Implicit || node.Page.IsIndex() {
+			continue
+		}
+
+		for parent := ph.Pages[node.Parent]; parent != nil && parent.Implicit; parent = ph.Pages[parent.Parent] {
+			if node.Page.FrontMatter.Date.After(parent.Page.FrontMatter.Date) {
+				parent.Page.FrontMatter.Date = node.Page.FrontMatter.Date
+			}
+		}
+	}
+}
+
+// isDescendant returns true if the page at path is under the section at
+// sectionPath.
+func (ph *ContentHierarchy) isDescendant(path, sectionPath string) bool {
+	if sectionPath == "" {
+		return path != ""
+	}
+
+	for node := ph.Pages[path]; node != nil && node.Parent != ""; node = ph.Pages[node.Parent] {
+		if node.Parent == sectionPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetChildren returns the pages listed by an index page: its child pages, or
+// all the pages under it when the index is recursive. Implicit sections are
+// not listed.
 func (ph *ContentHierarchy) GetChildren(page content.WebPage) []*content.WebPage {
 	path := page.RenderedPath()
 	if ph.childrenCache == nil {
@@ -204,19 +942,51 @@ func (ph *ContentHierarchy) GetChildren(page content.WebPage) []*content.WebPage
 		return ph.childrenCache[path]
 	}
 
+	recursive := page.FrontMatter.Index.Recursive
 	children := []*content.WebPage{}
-	for _, node := range ph.Pages {
-		if node.Parent == path {
+	for childPath, node := range ph.Pages {
+		if node.Implicit || childPath == path || node.Page.LanguagePrefix() != page.LanguagePrefix() {
+			continue
+		}
+
+		if recursive {
+			if !node.Page.IsIndex() && ph.isDescendant(childPath, path) {
+				children = append(children, node.Page)
+			}
+		} else if node.Parent == path {
 			children = append(children, node.Page)
 		}
 	}
 
-	slices.SortStableFunc(children, comparePageByDate)
+	slices.SortStableFunc(children, ph.ChildComparator(&page))
 	ph.childrenCache[path] = children
 
 	return children
 }
 
+// GetSubsections returns the sections directly under a section, including
+// implicit ones.
+func (ph *ContentHierarchy) GetSubsections(page content.WebPage) []*content.WebPage {
+	path := page.RenderedPath()
+	subsections := []*content.WebPage{}
+	for childPath, node := range ph.Pages {
+		if childPath != path && node.Parent == path && node.Page.IsIndex() &&
+			node.Page.LanguagePrefix() == page.LanguagePrefix() {
+			subsections = append(subsections, node.Page)
+		}
+	}
+
+	slices.SortStableFunc(subsections, ph.ChildComparator(&page))
+
+	return subsections
+}
+
+// IsImplicit returns true if the page is an implicit section.
+func (ph *ContentHierarchy) IsImplicit(page *content.WebPage) bool {
+	node, ok := ph.Pages[page.RenderedPath()]
+	return ok && node.Implicit
+}
+
 func (ph *ContentHierarchy) GetPage(path string) *content.WebPage {
 	node, ok := ph.Pages[path]
 	if ok {
@@ -236,6 +1006,37 @@ func (ph *ContentHierarchy) GetParent(page content.WebPage) *content.WebPage {
 	return nil
 }
 
+// GetAncestors returns the pages above the page at path, from the root index
+// page down to its parent. Implicit sections are left out as they are not
+// rendered.
+func (ph *ContentHierarchy) GetAncestors(path string) []*content.WebPage {
+	parent := ""
+	if node, ok := ph.Pages[path]; ok {
+		parent = node.Parent
+	} else if _, ok := ph.Pages[filepath.Dir(path)]; ok {
+		// generated pages like taxonomy terms are not in the hierarchy
+		parent = filepath.Dir(path)
+	}
+
+	ancestors := []*content.WebPage{}
+	for parent != "" {
+		node := ph.Pages[parent]
+		if !node.Implicit {
+			ancestors = append(ancestors, node.Page)
+		}
+		parent = node.Parent
+	}
+
+	// the home page of a translation is already its top ancestor
+	home := ph.LanguagePrefix(path)
+	if root, ok := ph.Pages[home]; ok && path != home && !slices.Contains(ancestors, root.Page) {
+		ancestors = append(ancestors, root.Page)
+	}
+	slices.Reverse(ancestors)
+
+	return ancestors
+}
+
 func (ph *ContentHierarchy) GetNextPage(parent *content.WebPage, child *content.WebPage) *content.WebPage {
 	children := ph.GetChildren(*parent)
 	for i, page := range children {
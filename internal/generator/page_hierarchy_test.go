@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/content"
+	"github.com/stretchr/testify/assert"
+)
+
+func hierarchyWithAliases(aliases map[string][]string) *ContentHierarchy {
+	ph := &ContentHierarchy{Pages: make(map[string]*ContentNode)}
+	for markdownPath, pageAliases := range aliases {
+		page := content.NewPage(nil, markdownPath, content.FrontMatter{Aliases: pageAliases})
+		ph.Pages[page.RenderedPath()] = &ContentNode{Page: page}
+	}
+
+	return ph
+}
+
+func TestValidateAliases(t *testing.T) {
+	ph := hierarchyWithAliases(map[string][]string{
+		"about.md":       {"/about-us/", "info"},
+		"posts/day-1.md": {"/old/day-1/"},
+	})
+	assert.NoError(t, ph.validateAliases())
+
+	tests := map[string]struct {
+		aliases map[string][]string
+		err     string
+	}{
+		"empty": {
+			aliases: map[string][]string{"about.md": {""}},
+			err:     "about.md: the alias \"\" is empty",
+		},
+		"root": {
+			aliases: map[string][]string{"about.md": {"/"}},
+			err:     "about.md: the alias \"/\" is empty",
+		},
+		"outside the site": {
+			aliases: map[string][]string{"about.md": {"/old/../../etc/"}},
+			err:     "about.md: the alias \"/old/../../etc/\" is outside the site",
+		},
+		"path of a page": {
+			aliases: map[string][]string{"about.md": {"/contact/"}, "contact.md": nil},
+			err:     "about.md: the alias \"/contact/\" is the path of contact.md",
+		},
+		"alias of another page": {
+			aliases: map[string][]string{"about.md": {"/old/"}, "contact.md": {"old"}},
+			err:     "about.md and contact.md both have the alias \"/old/\"",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, hierarchyWithAliases(test.aliases).validateAliases(), test.err)
+		})
+	}
+}
@@ -1,16 +1,20 @@
 package generator
 
 import (
-	"bytes"
+	"cmp"
 	"fmt"
 	htmltpl "html/template"
+	"maps"
 	"os"
 	"path"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"codeberg.org/asartalo/assg/internal/config"
 	"codeberg.org/asartalo/assg/internal/content"
+	"codeberg.org/asartalo/assg/internal/markdown"
 	"github.com/gertd/go-pluralize"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -20,6 +24,9 @@ type PageGenerator struct {
 	mg        *Generator
 	Config    *config.Config
 	hierarchy *ContentHierarchy
+	// usedTermPages are the term pages rendered in the current build, see
+	// WarnUnusedTermPages
+	usedTermPages map[*content.WebPage]bool
 }
 
 func (pg *PageGenerator) Printf(format string, args ...any) {
@@ -51,9 +58,20 @@ func (pg *PageGenerator) GeneratePage(page *content.WebPage, now time.Time) (err
 	pg.Printf("  Using template: %s\n", templateToUse)
 
 	pagePath := page.RenderedPath()
+	pg.mg.renderingPath = page.RootPath()
+	pg.mg.renderingTaxonomy = ""
+	pg.mg.renderingTerm = ""
 	templateData := pg.PageToTemplateContent(page)
 	pg.Printf("  Destination: %s\n", pagePath)
 
+	for _, alias := range page.FrontMatter.Aliases {
+		pg.Printf("  Alias: %s\n", alias)
+		err = pg.renderAlias(page, alias)
+		if err != nil {
+			return err
+		}
+	}
+
 	if page.IsTaxonomy() {
 		err = pg.generateTaxonomyPages(
 			page,
@@ -104,22 +122,105 @@ func (pg *PageGenerator) GetTemplateToUse(page *content.WebPage) string {
 
 func (pg *PageGenerator) PageToTemplateContent(page *content.WebPage) TemplateContent {
 	summary := ""
-	actualSummary, err := page.Summary()
+	actualSummary, err := page.Summary(pg.Config.SummaryLength)
 	if err == nil {
 		summary = actualSummary
 	}
 
+	mdConfig := pg.Config.Markdown
+	tocHtml := markdown.RenderTableOfContents(
+		markdown.HeadingTree(page.Headings, mdConfig.TocMinLevel, mdConfig.TocMaxLevel),
+	)
+
+	ancestors := pg.Ancestors(page.RenderedPath())
+
 	return TemplateContent{
-		FrontMatter: page.FrontMatter,
-		Content:     htmltpl.HTML(string(page.Content.String())),
-		Config:      *pg.Config,
-		RootPath:    page.RootPath(),
-		Permalink:   pg.mg.FullUrl(page.RootPath()),
-		Path:        page.RenderedPath(),
-		Summary:     htmltpl.HTML(summary),
+		FrontMatter:         page.FrontMatter,
+		Content:             htmltpl.HTML(string(page.Content.String())),
+		Config:              *pg.Config,
+		Site:                pg.mg.site,
+		RootPath:            page.RootPath(),
+		Permalink:           pg.mg.FullUrl(page.RootPath()),
+		Path:                page.RenderedPath(),
+		Summary:             htmltpl.HTML(summary),
+		TableOfContents:     markdown.HeadingTree(page.Headings, 1, 6),
+		TableOfContentsHtml: htmltpl.HTML(tocHtml),
+		WordCount:           page.WordCount,
+		ReadingTime:         page.ReadingTime,
+		Resources:           pg.pageResources(page),
+		Ancestors:           ancestors,
+		Breadcrumbs:         append(slices.Clone(ancestors), pg.breadcrumb(page)),
+		Language:            pg.pageLanguage(page),
+		Translations:        pg.translations(page),
+	}
+}
+
+// pageLanguage returns the language of a page, the site's if it has none.
+func (pg *PageGenerator) pageLanguage(page *content.WebPage) string {
+	return cmp.Or(
+		page.FrontMatter.Language,
+		pg.hierarchy.LanguagePrefix(page.RenderedPath()),
+		pg.Config.SiteLanguage(),
+	)
+}
+
+func (pg *PageGenerator) translations(page *content.WebPage) []Translation {
+	translations := []Translation{}
+	for _, translation := range pg.hierarchy.GetTranslations(page) {
+		translations = append(translations, Translation{
+			Language:  pg.pageLanguage(translation),
+			Title:     translation.FrontMatter.Title,
+			RootPath:  translation.RootPath(),
+			Permalink: pg.mg.FullUrl(translation.RootPath()),
+		})
+	}
+
+	return translations
+}
+
+// Ancestors returns links to the pages above the page at pagePath.
+func (pg *PageGenerator) Ancestors(pagePath string) []Breadcrumb {
+	crumbs := []Breadcrumb{}
+	for _, ancestor := range pg.hierarchy.GetAncestors(pagePath) {
+		crumbs = append(crumbs, pg.breadcrumb(ancestor))
+	}
+
+	return crumbs
+}
+
+// Breadcrumbs returns links to the pages above the page at pagePath followed
+// by the page itself.
+func (pg *PageGenerator) Breadcrumbs(pagePath string) []Breadcrumb {
+	crumbs := pg.Ancestors(pagePath)
+	if page := pg.hierarchy.GetPage(pagePath); page != nil {
+		crumbs = append(crumbs, pg.breadcrumb(page))
+	}
+
+	return crumbs
+}
+
+func (pg *PageGenerator) breadcrumb(page *content.WebPage) Breadcrumb {
+	return Breadcrumb{
+		Title:     page.FrontMatter.Title,
+		RootPath:  page.RootPath(),
+		Permalink: pg.mg.FullUrl(page.RootPath()),
 	}
 }
 
+func (pg *PageGenerator) pageResources(page *content.WebPage) []PageResource {
+	resources := []PageResource{}
+	for _, name := range page.Resources {
+		rootPath := path.Join(page.ResourcesPath, name)
+		resources = append(resources, PageResource{
+			Name:      name,
+			RootPath:  rootPath,
+			Permalink: pg.mg.FullUrl(rootPath),
+		})
+	}
+
+	return resources
+}
+
 func (pg *PageGenerator) PagesToTemplateContents(indexPage *content.WebPage) [][]TemplateContent {
 	childPages := pg.hierarchy.GetChildren(*indexPage)
 
@@ -149,6 +250,34 @@ func (pg *PageGenerator) GetSectionPages(indexPath string, max int, offset int)
 	return sectionPages
 }
 
+// GetSubsections returns the sections under a section with their pages.
+func (pg *PageGenerator) GetSubsections(section *content.WebPage) []SectionTemplateContent {
+	subsections := []SectionTemplateContent{}
+	for _, subsection := range pg.hierarchy.GetSubsections(*section) {
+		pages := []TemplateContent{}
+		for _, page := range pg.hierarchy.GetChildren(*subsection) {
+			pages = append(pages, pg.PageToTemplateContent(page))
+		}
+
+		implicit := pg.hierarchy.IsImplicit(subsection)
+		templateContent := pg.PageToTemplateContent(subsection)
+		if implicit {
+			// implicit sections are not rendered so there is nothing to link to
+			templateContent.RootPath = ""
+			templateContent.Permalink = ""
+		}
+
+		subsections = append(subsections, SectionTemplateContent{
+			TemplateContent: templateContent,
+			Pages:           pages,
+			Subsections:     pg.GetSubsections(subsection),
+			Implicit:        implicit,
+		})
+	}
+
+	return subsections
+}
+
 func (pg *PageGenerator) generateTaxonomyPages(
 	page *content.WebPage,
 	templateData TemplateContent,
@@ -158,47 +287,59 @@ func (pg *PageGenerator) generateTaxonomyPages(
 ) (err error) {
 	pg.Printf("  Generating taxonomy pages for: %s\n", page.MarkdownPath)
 	taxonomy := page.TaxonomyType()
-	termMapping := pg.hierarchy.GetTaxonomyTerms(taxonomy)
-	paginateBy := page.FrontMatter.Index.PaginateBy
+	key := taxonomyKey(page.LanguagePrefix(), taxonomy)
+	termMapping := pg.hierarchy.GetTaxonomyTerms(key)
+	taxonomyConfig, _ := pg.Config.FindTaxonomy(taxonomy)
+	paginateBy := cmp.Or(page.FrontMatter.Index.PaginateBy, taxonomyConfig.PaginateBy)
 
 	err = pg.renderPage(templateData, pagePath, templateToUse, true)
 	if err != nil {
 		return err
 	}
 
-	titleCaser := cases.Title(language.English).String
 	indexTemplateToUse := page.FrontMatter.Index.PageTemplate
 	pluralizer := pluralize.NewClient()
-	taxonomySingular := pluralizer.Singular(titleCaser(taxonomy))
+	taxonomySingular := pluralizer.Singular(titleCase(taxonomy))
 	for term, pages := range termMapping {
-		termDir := path.Join(pagePath, dashSpaces(term))
+		slug := pg.hierarchy.TermSlug(taxonomy, term)
+		name := titleCase(pg.hierarchy.TermName(taxonomy, term))
+		termDir := path.Join(pagePath, slug)
+		pg.mg.renderingPath = content.RootPath(termDir)
+		pg.mg.renderingTaxonomy = key
+		pg.mg.renderingTerm = term
 		taxIndexFields := page.FrontMatter.Index
 		iPageFrontMatter := content.FrontMatter{
-			Title: titleCaser(term),
+			Title: name,
 			Date:  now,
 			Description: fmt.Sprintf(
 				"%s: %s",
 				taxonomySingular,
-				titleCaser(term),
+				name,
 			),
 			Index:    taxIndexFields,
 			Template: page.FrontMatter.Index.PageTemplate,
 		}
-		termPage := content.WebPage{
-			FrontMatter: iPageFrontMatter,
-			Content:     *new(bytes.Buffer),
-			MarkdownPath: path.Join(
+		termPage := content.NewPage(
+			pg.mg.markdown,
+			path.Join(
 				page.RenderedPath(),
-				fmt.Sprintf("%s.md", dashSpaces(term)),
+				fmt.Sprintf("%s.md", slug),
 			),
+			iPageFrontMatter,
+		)
+		termTemplateToUse := indexTemplateToUse
+		if markdownPage := pg.hierarchy.GetTermPage(page, term); markdownPage != nil {
+			pg.usedTermPages[markdownPage] = true
+			termPage = mergeTermPage(markdownPage, iPageFrontMatter)
+			termTemplateToUse = termPage.FrontMatter.Template
 		}
 
 		err = pg.generateIndexPages(
-			&termPage,
-			pg.PageToTemplateContent(&termPage),
+			termPage,
+			pg.PageToTemplateContent(termPage),
 			termDir,
-			indexTemplateToUse,
-			PaginateTransform(pages, paginateBy, pg.PageToTemplateContent),
+			termTemplateToUse,
+			PaginateTransform(pages, cmp.Or(paginateBy, len(pages)), pg.PageToTemplateContent),
 		)
 
 		if err != nil {
@@ -209,6 +350,33 @@ func (pg *PageGenerator) generateTaxonomyPages(
 	return
 }
 
+// mergeTermPage fills in the front matter of a term's Markdown page with the
+// generated one. The term's listing always uses the taxonomy's index settings.
+func mergeTermPage(page *content.WebPage, generated content.FrontMatter) *content.WebPage {
+	merged := *page
+	frontMatter := &merged.FrontMatter
+	frontMatter.Title = cmp.Or(frontMatter.Title, generated.Title)
+	frontMatter.Description = cmp.Or(frontMatter.Description, generated.Description)
+	frontMatter.Template = cmp.Or(frontMatter.Template, generated.Template)
+	frontMatter.Index = generated.Index
+	if frontMatter.Date.IsZero() {
+		frontMatter.Date = generated.Date
+	}
+
+	return &merged
+}
+
+// WarnUnusedTermPages warns about the Markdown pages in a taxonomy's directory
+// that were not rendered because no page has their term.
+func (pg *PageGenerator) WarnUnusedTermPages() {
+	for _, path := range slices.Sorted(maps.Keys(pg.hierarchy.termPages)) {
+		page := pg.hierarchy.termPages[path]
+		if !pg.usedTermPages[page] {
+			pg.hierarchy.Warnf("%s is not the page of any term", page.MarkdownPath)
+		}
+	}
+}
+
 func (pg *PageGenerator) generateChildPageData(
 	page *content.WebPage,
 	parentPage *content.WebPage,
@@ -247,14 +415,55 @@ func (pg *PageGenerator) renderPage(
 	canonical bool,
 ) error {
 	g := pg.mg
-	destinationDir := g.OutputPath(pagePath)
-	err := os.MkdirAll(destinationDir, 0755)
+	filePath := path.Join(pagePath, "index.html")
+	err := g.claimOutput(filePath, fmt.Sprintf("the page at %s", content.RootPath(pagePath)))
 	if err != nil {
 		return err
 	}
 
 	pg.Printf("  Rendering page: %s\n", g.pathValue(templateData))
-	destinationPath := path.Join(destinationDir, "index.html")
+	err = pg.writePage(templateData, g.OutputPath(filePath), templateToUse)
+	if err != nil {
+		return err
+	}
+
+	if canonical {
+		g.renderedPaths = append(g.renderedPaths, content.RootPath(pagePath))
+	}
+
+	return nil
+}
+
+// renderAlias writes a redirect to the page at the alias. Aliases ending in
+// .html or .htm are written as that file instead of a directory index.
+func (pg *PageGenerator) renderAlias(page *content.WebPage, alias string) error {
+	g := pg.mg
+	filePath := strings.Trim(alias, "/")
+	if !isHTMLFile(filePath) {
+		filePath = path.Join(filePath, "index.html")
+	}
+
+	owner := fmt.Sprintf("the alias \"%s\" of %s", alias, page.MarkdownPath)
+	err := g.claimOutput(filePath, owner)
+	if err != nil {
+		return err
+	}
+
+	return pg.writePage(g.FullUrl(page.RootPath()), g.OutputPath(filePath), "_redirect")
+}
+
+func isHTMLFile(filePath string) bool {
+	ext := strings.ToLower(path.Ext(filePath))
+	return ext == ".html" || ext == ".htm"
+}
+
+func (pg *PageGenerator) writePage(templateData any, destinationPath string, templateToUse string) error {
+	g := pg.mg
+	err := os.MkdirAll(path.Dir(destinationPath), 0755)
+	if err != nil {
+		return err
+	}
+
 	destinationFile, err := os.OpenFile(destinationPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		pg.Printf("Error creating file")
@@ -278,11 +487,7 @@ func (pg *PageGenerator) renderPage(
 		return err
 	}
 
-	if canonical {
-		g.renderedPaths = append(g.renderedPaths, content.RootPath(pagePath))
-	}
-
-	return err
+	return nil
 }
 
 func (pg *PageGenerator) generateIndexPages(
@@ -295,6 +500,7 @@ func (pg *PageGenerator) generateIndexPages(
 	g := pg.mg
 	pg.Printf("  Generating index pages for: %s\n", page.MarkdownPath)
 	pagingCount := len(pagingGroups)
+	subsections := pg.GetSubsections(page)
 
 	// render redirect page
 	if pagingCount > 1 {
@@ -335,6 +541,7 @@ func (pg *PageGenerator) generateIndexPages(
 		indexTemplateData := IndexTemplateContent{
 			TemplateContent: templateData,
 			Pages:           group,
+			Subsections:     subsections,
 			Prev:            prev,
 			Next:            next,
 			CurrentPage:     i + 1,
@@ -346,3 +553,8 @@ func (pg *PageGenerator) generateIndexPages(
 
 	return
 }
+
+// titleCase capitalizes the words of a taxonomy or term for titles.
+func titleCase(str string) string {
+	return cases.Title(language.English).String(str)
+}
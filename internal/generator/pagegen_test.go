@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/content"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTermPage(t *testing.T) {
+	page := content.NewPage(nil, "tags/coffee.md", content.FrontMatter{Title: "All About Coffee"})
+	generated := content.FrontMatter{
+		Title:       "Coffee",
+		Description: "Pages tagged Coffee",
+		Template:    "tag.html",
+	}
+
+	merged := mergeTermPage(page, generated)
+
+	assert.Equal(t, "All About Coffee", merged.FrontMatter.Title)
+	assert.Equal(t, "Pages tagged Coffee", merged.FrontMatter.Description)
+	assert.Equal(t, "tag.html", merged.FrontMatter.Template)
+	assert.Equal(t, content.FrontMatter{Title: "All About Coffee"}, page.FrontMatter)
+}
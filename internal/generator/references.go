@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"codeberg.org/asartalo/assg/internal/content"
+	"codeberg.org/asartalo/assg/internal/markdown"
+)
+
+// referenceCandidates returns the source paths a reference can point to. A
+// reference without an extension, like [[posts/day-1]], can be a Markdown
+// file or a page bundle.
+func referenceCandidates(sourcePath string) []string {
+	if path.Ext(sourcePath) != "" {
+		return []string{sourcePath}
+	}
+
+	return []string{sourcePath + ".md", path.Join(sourcePath, "index.md")}
+}
+
+// resolveReferences replaces the links to other pages by their source path
+// (see markdown.ReferencePrefix) with the pages' root paths. Links to missing
+// or unpublished pages are errors, or warnings when warnBrokenLinks is set.
+func (ph *ContentHierarchy) resolveReferences() error {
+	sources := make(map[string]*content.WebPage)
+	for _, node := range ph.Pages {
+		if !node.Implicit {
+			sources[filepath.ToSlash(node.Page.MarkdownPath)] = node.Page
+		}
+	}
+	for _, page := range ph.termPages {
+		sources[filepath.ToSlash(page.MarkdownPath)] = page
+	}
+
+	resolve := func(sourcePath string) (markdown.PageReference, bool) {
+		for _, candidate := range referenceCandidates(sourcePath) {
+			if page, ok := sources[candidate]; ok {
+				return markdown.PageReference{
+					RootPath: page.RootPath(),
+					Title:    page.FrontMatter.Title,
+				}, true
+			}
+		}
+
+		return markdown.PageReference{}, false
+	}
+
+	paths := make([]string, 0, len(sources))
+	for sourcePath := range sources {
+		paths = append(paths, sourcePath)
+	}
+	slices.Sort(paths)
+
+	errs := []error{}
+	for _, sourcePath := range paths {
+		page := sources[sourcePath]
+		if !strings.Contains(page.Content.String(), markdown.ReferencePrefix) {
+			continue
+		}
+
+		resolved, missing := markdown.ResolveReferences(page.Content.String(), resolve)
+		page.Content.Reset()
+		page.Content.WriteString(resolved)
+
+		page.Excerpt, _ = markdown.ResolveReferences(page.Excerpt, resolve)
+
+		slices.Sort(missing)
+		for _, target := range slices.Compact(missing) {
+			err := fmt.Errorf("%s: link to %s page \"%s\"", page.MarkdownPath, ph.brokenReason(target), target)
+			if ph.warnBrokenLinks {
+				ph.Warnf("%v", err)
+			} else {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ph *ContentHierarchy) brokenReason(sourcePath string) string {
+	for _, candidate := range referenceCandidates(sourcePath) {
+		if reason, ok := ph.unpublished[candidate]; ok {
+			return reason
+		}
+	}
+
+	return "missing"
+}
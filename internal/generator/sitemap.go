@@ -3,21 +3,35 @@ package generator
 import (
 	"encoding/xml"
 	"io"
+	"regexp"
 )
 
 // Sitemap represents the sitemap of the site.
 type Sitemap struct {
-	XMLName xml.Name `xml:"urlset"`
-	Xmlns   string   `xml:"xmlns,attr"`
-	Urls    []*SitemapUrl
+	XMLName    xml.Name `xml:"urlset"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	XmlnsXhtml string   `xml:"xmlns:xhtml,attr,omitempty"`
+	Urls       []*SitemapUrl
 }
 
 // SitemapUrl represents a URL in the sitemap.
 type SitemapUrl struct {
-	XMLName xml.Name `xml:"url"`
-	Loc     string   `xml:"loc"`
+	XMLName    xml.Name `xml:"url"`
+	Loc        string   `xml:"loc"`
+	Lastmod    string   `xml:"lastmod,omitempty"`
+	Alternates []*SitemapAlternate
 }
 
+// SitemapAlternate links a URL in the sitemap to a translation of its page.
+type SitemapAlternate struct {
+	XMLName  xml.Name `xml:"xhtml:link"`
+	Rel      string   `xml:"rel,attr"`
+	Hreflang string   `xml:"hreflang,attr"`
+	Href     string   `xml:"href,attr"`
+}
+
+var alternateEndRegexp = regexp.MustCompile(`></xhtml:link>`)
+
 // WriteXML writes the sitemap to the writer.
 func (s *Sitemap) WriteXML(wr io.Writer) error {
 	output, err := xml.MarshalIndent(s, "", "  ")
@@ -30,7 +44,7 @@ func (s *Sitemap) WriteXML(wr io.Writer) error {
 		return err
 	}
 
-	_, err = wr.Write(output)
+	_, err = wr.Write(alternateEndRegexp.ReplaceAll(output, []byte("/>")))
 	if err != nil {
 		return err
 	}
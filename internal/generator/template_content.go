@@ -5,16 +5,60 @@ import (
 
 	"codeberg.org/asartalo/assg/internal/config"
 	"codeberg.org/asartalo/assg/internal/content"
+	"codeberg.org/asartalo/assg/internal/markdown"
 )
 
 type TemplateContent struct {
 	content.FrontMatter
-	Content   htmltpl.HTML
-	Config    config.Config
-	Path      string
+	Content             htmltpl.HTML
+	Config              config.Config
+	Site                SiteContent
+	Path                string
+	RootPath            string
+	Permalink           string
+	Summary             htmltpl.HTML
+	TableOfContents     []*markdown.Heading
+	TableOfContentsHtml htmltpl.HTML
+	WordCount           int
+	ReadingTime         int
+	Resources           []PageResource
+	// Ancestors are the pages above this page, starting from the root
+	Ancestors []Breadcrumb
+	// Breadcrumbs are the Ancestors followed by this page
+	Breadcrumbs []Breadcrumb
+	// Language is the page's language, the site's default if not set
+	Language string
+	// Translations are the other translations of this page
+	Translations []Translation
+}
+
+// Translation is a link to a page in another language.
+type Translation struct {
+	Language  string
+	Title     string
+	RootPath  string
+	Permalink string
+}
+
+// Breadcrumb is a link to a page in the ancestor chain of another page.
+type Breadcrumb struct {
+	Title     string
+	RootPath  string
+	Permalink string
+}
+
+// SiteContent is what templates get of the whole site as .Site.
+type SiteContent struct {
+	// Data holds the files in the data directory (see content.LoadData)
+	Data map[string]any
+}
+
+// PageResource is a file in a page bundle's directory.
+type PageResource struct {
+	// Name is the path of the file relative to the bundle
+	Name      string
 	RootPath  string
 	Permalink string
-	Summary   htmltpl.HTML
 }
 
 func (t TemplateContent) HasExtra(key string) bool {
@@ -37,17 +81,37 @@ type PaginatedTemplateContent struct {
 type IndexTemplateContent struct {
 	TemplateContent
 	Pages       []TemplateContent
+	Subsections []SectionTemplateContent
 	Prev        string
 	Next        string
 	CurrentPage int
 	TotalPages  int
 }
 
+// SectionTemplateContent is a section under an index page with all its pages.
+type SectionTemplateContent struct {
+	TemplateContent
+	Pages       []TemplateContent
+	Subsections []SectionTemplateContent
+	// Implicit is true for a directory without a page, which is not rendered.
+	// Its RootPath and Permalink are empty so templates don't link to it.
+	Implicit bool
+}
+
 type TaxonomyTermContent struct {
-	Term      string
+	Term string
+	// Name is the last part of a term of a hierarchical taxonomy, like "go"
+	// for "tech/go", and the term itself in other taxonomies
+	Name string
+	// Parent is the term above this one in a hierarchical taxonomy
+	Parent string
+	// Children are the terms below this one in a hierarchical taxonomy
+	Children  []*TaxonomyTermContent
 	PageCount int
 	Permalink string
 	RootPath  string
+	// FeedUrl is the URL of the term's feed, empty if its taxonomy has none
+	FeedUrl string
 }
 
 type TermIndexTemplateContent struct {
@@ -0,0 +1,186 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// CountPlaceholder is replaced by the count in plural messages.
+const CountPlaceholder = "{count}"
+
+var pluralForms = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// Message is a translated string. Messages that depend on a count have a text
+// for each plural form ("zero", "one", "two", "few", "many" and "other").
+type Message struct {
+	Text  string
+	Forms map[plural.Form]string
+}
+
+// Translations holds the messages of each language, read from TOML files
+// named after the language, like i18n/fr.toml.
+type Translations struct {
+	defaultLanguage string
+	languages       map[string]map[string]Message
+}
+
+// New creates translations without any messages.
+func New(defaultLanguage string) *Translations {
+	return &Translations{
+		defaultLanguage: defaultLanguage,
+		languages:       make(map[string]map[string]Message),
+	}
+}
+
+// Load reads the TOML files in dir. A missing directory has no messages.
+func Load(dir string, defaultLanguage string) (*Translations, error) {
+	translations := New(defaultLanguage)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return translations, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".toml" {
+			continue
+		}
+
+		filePath := filepath.Join(dir, entry.Name())
+		data := make(map[string]any)
+		if _, err := toml.DecodeFile(filePath, &data); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", filePath, err)
+		}
+
+		lang := strings.TrimSuffix(entry.Name(), ".toml")
+		if err := translations.Add(lang, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+	}
+
+	return translations, nil
+}
+
+// Add adds the messages of a language. A value is either the text or a table
+// of the texts of each plural form.
+func (t *Translations) Add(lang string, data map[string]any) error {
+	messages := t.languages[lang]
+	if messages == nil {
+		messages = make(map[string]Message)
+		t.languages[lang] = messages
+	}
+
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			messages[key] = Message{Text: v}
+		case map[string]any:
+			message := Message{Forms: make(map[plural.Form]string)}
+			for name, text := range v {
+				form, ok := pluralForms[name]
+				if !ok {
+					return fmt.Errorf("unknown plural form \"%s\" in \"%s\"", name, key)
+				}
+
+				str, ok := text.(string)
+				if !ok {
+					return fmt.Errorf("the \"%s\" form of \"%s\" is not a string", name, key)
+				}
+				message.Forms[form] = str
+			}
+
+			if _, ok := message.Forms[plural.Other]; !ok {
+				return fmt.Errorf("\"%s\" has no \"other\" form", key)
+			}
+			messages[key] = message
+		default:
+			return fmt.Errorf("\"%s\" is not a string or a table of plural forms", key)
+		}
+	}
+
+	return nil
+}
+
+// lookup finds the message in the language or its base language, e.g. "fr"
+// for "fr-CA".
+func (t *Translations) lookup(lang, key string) (Message, string, bool) {
+	for _, candidate := range []string{lang, strings.SplitN(lang, "-", 2)[0]} {
+		if message, ok := t.languages[candidate][key]; ok {
+			return message, candidate, true
+		}
+	}
+
+	return Message{}, "", false
+}
+
+// Has returns true if the language has a message for key.
+func (t *Translations) Has(lang, key string) bool {
+	_, _, ok := t.lookup(lang, key)
+	return ok
+}
+
+// Translate returns the message for key in the language, or in the default
+// language if the language doesn't have it. The first count picks the plural
+// form and replaces {count}. The key itself is returned if no language has
+// the message.
+func (t *Translations) Translate(lang, key string, count ...int) (string, bool) {
+	message, found, ok := t.lookup(lang, key)
+	if !ok {
+		message, found, ok = t.lookup(t.defaultLanguage, key)
+	}
+
+	if !ok {
+		return key, false
+	}
+
+	if len(count) == 0 {
+		if message.Forms != nil {
+			return message.formText(plural.Other), true
+		}
+
+		return message.Text, true
+	}
+
+	text := message.Text
+	if message.Forms != nil {
+		text = message.formText(pluralForm(found, count[0]))
+	}
+
+	return strings.ReplaceAll(text, CountPlaceholder, strconv.Itoa(count[0])), true
+}
+
+func pluralForm(lang string, count int) plural.Form {
+	if count < 0 {
+		count = -count
+	}
+
+	return plural.Cardinal.MatchPlural(language.Make(lang), count, 0, 0, 0, 0)
+}
+
+// formText returns the text of a plural form, or the "other" form if the
+// message doesn't have it.
+func (m Message) formText(form plural.Form) string {
+	if text, ok := m.Forms[form]; ok {
+		return text
+	}
+
+	return m.Forms[plural.Other]
+}
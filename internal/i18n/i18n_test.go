@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTranslations(t *testing.T) *Translations {
+	dir := t.TempDir()
+	files := map[string]string{
+		"en.toml": `
+next_page = "Next page"
+tags = "Tags"
+
+[posts]
+one = "{count} post"
+other = "{count} posts"
+`,
+		"fr.toml": `
+next_page = "Page suivante"
+
+[posts]
+one = "{count} article"
+other = "{count} articles"
+`,
+		"ru.toml": `
+[posts]
+one = "{count} запись"
+few = "{count} записи"
+many = "{count} записей"
+other = "{count} записи"
+`,
+	}
+	for name, data := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0600))
+	}
+
+	translations, err := Load(dir, "en")
+	assert.NoError(t, err)
+
+	return translations
+}
+
+func TestTranslate(t *testing.T) {
+	translations := testTranslations(t)
+
+	text, ok := translations.Translate("fr", "next_page")
+	assert.True(t, ok)
+	assert.Equal(t, "Page suivante", text)
+
+	text, ok = translations.Translate("fr-CA", "next_page")
+	assert.True(t, ok)
+	assert.Equal(t, "Page suivante", text)
+}
+
+func TestTranslateFallsBackToDefaultLanguage(t *testing.T) {
+	translations := testTranslations(t)
+
+	text, ok := translations.Translate("fr", "tags")
+	assert.True(t, ok)
+	assert.Equal(t, "Tags", text)
+	assert.False(t, translations.Has("fr", "tags"))
+
+	text, ok = translations.Translate("fr", "missing")
+	assert.False(t, ok)
+	assert.Equal(t, "missing", text)
+}
+
+func TestTranslatePlurals(t *testing.T) {
+	translations := testTranslations(t)
+
+	cases := []struct {
+		lang     string
+		count    int
+		expected string
+	}{
+		{"en", 1, "1 post"},
+		{"en", 0, "0 posts"},
+		{"en", 2, "2 posts"},
+		{"fr", 0, "0 article"},
+		{"fr", 1, "1 article"},
+		{"fr", 5, "5 articles"},
+		{"ru", 1, "1 запись"},
+		{"ru", 3, "3 записи"},
+		{"ru", 5, "5 записей"},
+		{"ru", 21, "21 запись"},
+	}
+
+	for _, c := range cases {
+		text, _ := translations.Translate(c.lang, "posts", c.count)
+		assert.Equal(t, c.expected, text, "%s %d", c.lang, c.count)
+	}
+}
+
+func TestLoadRejectsUnknownPluralForms(t *testing.T) {
+	dir := t.TempDir()
+	data := "[posts]\nsingle = \"a post\"\nother = \"posts\"\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.toml"), []byte(data), 0600))
+
+	_, err := Load(dir, "en")
+	assert.ErrorContains(t, err, `unknown plural form "single" in "posts"`)
+}
+
+func TestLoadWithoutDirectory(t *testing.T) {
+	translations, err := Load(filepath.Join(t.TempDir(), "i18n"), "en")
+
+	assert.NoError(t, err)
+	assert.False(t, translations.Has("en", "tags"))
+}
@@ -0,0 +1,52 @@
+package markdown
+
+import (
+	"fmt"
+	"io"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+)
+
+// Highlighting returns an extension that highlights fenced code blocks at
+// build time using the configured style. When HighlightClasses is set, CSS
+// classes are emitted instead of inline styles.
+func Highlighting(cfg config.MarkdownConfig) (goldmark.Extender, error) {
+	if _, err := highlightStyle(cfg); err != nil {
+		return nil, err
+	}
+
+	return highlighting.NewHighlighting(
+		highlighting.WithStyle(cfg.HighlightStyle),
+		highlighting.WithFormatOptions(
+			chromahtml.WithClasses(cfg.HighlightClasses),
+		),
+	), nil
+}
+
+// highlightStyle returns the chroma style named by highlight_style.
+func highlightStyle(cfg config.MarkdownConfig) (*chroma.Style, error) {
+	style, ok := styles.Registry[cfg.HighlightStyle]
+	if !ok {
+		return nil, fmt.Errorf("unknown highlight style \"%s\" in highlight_style", cfg.HighlightStyle)
+	}
+
+	return style, nil
+}
+
+// WriteHighlightCSS writes the stylesheet that matches the class names
+// emitted when HighlightClasses is enabled.
+func WriteHighlightCSS(w io.Writer, cfg config.MarkdownConfig) error {
+	style, err := highlightStyle(cfg)
+	if err != nil {
+		return err
+	}
+
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+
+	return formatter.WriteCSS(w, style)
+}
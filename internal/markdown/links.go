@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var basePathKey = parser.NewContextKey()
+
+// SetBasePath makes relative link and image destinations in the page to be
+// parsed resolve against basePath (e.g. "/posts/day-1/").
+func SetBasePath(pc parser.Context, basePath string) {
+	pc.Set(basePathKey, basePath)
+}
+
+// ResolveRelative resolves dest against basePath. Destinations that are
+// absolute, root-relative, fragments or page references are returned as they
+// are.
+func ResolveRelative(basePath, dest string) string {
+	if dest == "" || strings.HasPrefix(dest, "/") || strings.HasPrefix(dest, "#") ||
+		strings.HasPrefix(dest, ReferencePrefix) {
+		return dest
+	}
+
+	ref, err := url.Parse(dest)
+	if err != nil || ref.Scheme != "" || ref.Host != "" {
+		return dest
+	}
+
+	base, err := url.Parse(basePath)
+	if err != nil {
+		return dest
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+type linkResolver struct{}
+
+func (r *linkResolver) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	basePath, _ := pc.Get(basePathKey).(string)
+	if basePath == "" {
+		return
+	}
+
+	gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *gast.Link:
+			node.Destination = []byte(ResolveRelative(basePath, string(node.Destination)))
+		case *gast.Image:
+			node.Destination = []byte(ResolveRelative(basePath, string(node.Destination)))
+		}
+
+		return gast.WalkContinue, nil
+	})
+}
+
+type relativeLinks struct{}
+
+// RelativeLinks is an extension that resolves relative links and images
+// against the base path set with SetBasePath.
+var RelativeLinks = &relativeLinks{}
+
+func (e *relativeLinks) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&linkResolver{}, 999),
+	))
+}
@@ -0,0 +1,25 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRelative(t *testing.T) {
+	base := "/posts/day-1/"
+	cases := map[string]string{
+		"photo.jpg":               "/posts/day-1/photo.jpg",
+		"files/route.txt":         "/posts/day-1/files/route.txt",
+		"../day-2/#map":           "/posts/day-2/#map",
+		"/about/":                 "/about/",
+		"#top":                    "#top",
+		"https://example.org/":    "https://example.org/",
+		"mailto:jane@example.com": "mailto:jane@example.com",
+		"":                        "",
+	}
+
+	for dest, expected := range cases {
+		assert.Equal(t, expected, ResolveRelative(base, dest), dest)
+	}
+}
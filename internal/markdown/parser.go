@@ -1,28 +1,76 @@
 package markdown
 
 import (
+	"codeberg.org/asartalo/assg/internal/config"
 	figure "github.com/mangoumbrella/goldmark-figure"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"go.abhg.dev/goldmark/frontmatter"
 )
 
-var Parser = goldmark.New(
-	goldmark.WithExtensions(
-		extension.Table,
-		extension.Strikethrough,
-		extension.Footnote,
+func extensions(site *config.Config) ([]goldmark.Extender, error) {
+	cfg := site.Markdown
+	extenders := []goldmark.Extender{
 		&frontmatter.Extender{},
-		figure.Figure,
-		Typographer,
-	),
-	goldmark.WithParserOptions(
-		parser.WithAutoHeadingID(),
-	),
-	goldmark.WithRendererOptions(
-		html.WithXHTML(),
-		html.WithUnsafe(),
-	),
-)
+		TableOfContents,
+		RelativeLinks,
+		WikiLinks,
+	}
+
+	optional := []struct {
+		enabled  bool
+		extender goldmark.Extender
+	}{
+		{cfg.Tables, extension.Table},
+		{cfg.Strikethrough, extension.Strikethrough},
+		{cfg.Footnotes, extension.Footnote},
+		{cfg.Figures, figure.Figure},
+		{cfg.DefinitionLists, extension.DefinitionList},
+		{cfg.TaskLists, extension.TaskList},
+		{cfg.Linkify, extension.Linkify},
+		{cfg.CJK, extension.CJK},
+		{cfg.SmartPunctuation, LocalizedTypographer(site)},
+	}
+
+	for _, opt := range optional {
+		if opt.enabled {
+			extenders = append(extenders, opt.extender)
+		}
+	}
+
+	if cfg.HighlightCode {
+		highlighter, err := Highlighting(cfg)
+		if err != nil {
+			return nil, err
+		}
+		extenders = append(extenders, highlighter)
+	}
+
+	return extenders, nil
+}
+
+// New creates a Markdown parser configured from the [markdown] section of the
+// site configuration. It fails on settings the extensions don't support, like
+// an unknown highlight_style.
+func New(cfg *config.Config) (goldmark.Markdown, error) {
+	exts, err := extensions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rendererOptions := []renderer.Option{html.WithXHTML()}
+	if cfg.Markdown.RawHTML {
+		rendererOptions = append(rendererOptions, html.WithUnsafe())
+	}
+
+	return goldmark.New(
+		goldmark.WithExtensions(exts...),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+		goldmark.WithRendererOptions(rendererOptions...),
+	), nil
+}
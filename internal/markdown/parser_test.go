@@ -0,0 +1,140 @@
+package markdown
+
+import (
+	"bytes"
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark"
+)
+
+const codeBlock = "```go\nfunc main() {}\n```\n"
+
+func newMarkdown(t *testing.T, cfg *config.Config) goldmark.Markdown {
+	md, err := New(cfg)
+	assert.NoError(t, err)
+
+	return md
+}
+
+func convert(t *testing.T, cfg *config.Config, source string) string {
+	var buf bytes.Buffer
+	err := newMarkdown(t, cfg).Convert([]byte(source), &buf)
+	assert.NoError(t, err)
+
+	return buf.String()
+}
+
+func TestCodeIsNotHighlightedByDefault(t *testing.T) {
+	html := convert(t, &config.Config{}, codeBlock)
+
+	assert.Equal(t, "<pre><code class=\"language-go\">func main() {}\n</code></pre>\n", html)
+}
+
+func TestCodeHighlightingWithInlineStyles(t *testing.T) {
+	cfg := &config.Config{
+		Markdown: config.MarkdownConfig{HighlightCode: true, HighlightStyle: "github"},
+	}
+	html := convert(t, cfg, codeBlock)
+
+	assert.Contains(t, html, `<pre style="background-color:#fff;">`)
+	assert.Contains(t, html, `<span style="color:#000;font-weight:bold">func</span>`)
+	assert.NotContains(t, html, `class=`)
+}
+
+func TestCodeHighlightingWithClasses(t *testing.T) {
+	cfg := &config.Config{
+		Markdown: config.MarkdownConfig{
+			HighlightCode:    true,
+			HighlightStyle:   "github",
+			HighlightClasses: true,
+		},
+	}
+	html := convert(t, cfg, codeBlock)
+
+	assert.Contains(t, html, `<pre class="chroma">`)
+	assert.Contains(t, html, `<span class="kd">func</span>`)
+	assert.NotContains(t, html, `style=`)
+}
+
+func TestWriteHighlightCSS(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteHighlightCSS(&buf, config.MarkdownConfig{HighlightStyle: "github"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), ".chroma .kd {")
+}
+
+func TestWriteHighlightCSSUnknownStyle(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteHighlightCSS(&buf, config.MarkdownConfig{HighlightStyle: "no-such-style"})
+
+	assert.Error(t, err)
+}
+
+func TestUnknownHighlightStyle(t *testing.T) {
+	_, err := New(&config.Config{
+		Markdown: config.MarkdownConfig{HighlightCode: true, HighlightStyle: "no-such-style"},
+	})
+
+	assert.EqualError(t, err, "unknown highlight style \"no-such-style\" in highlight_style")
+}
+
+func TestDefaultExtensions(t *testing.T) {
+	cfg := &config.Config{Markdown: config.DefaultMarkdownConfig()}
+	html := convert(t, cfg, "\"Hi\" ~~there~~ <em>you</em>\n\n- [ ] task\n")
+
+	assert.Equal(
+		t,
+		"<p>“Hi” <del>there</del> <em>you</em></p>\n<ul>\n<li>[ ] task</li>\n</ul>\n",
+		html,
+	)
+}
+
+func TestDisablingExtensions(t *testing.T) {
+	mdConfig := config.DefaultMarkdownConfig()
+	mdConfig.SmartPunctuation = false
+	mdConfig.Strikethrough = false
+	mdConfig.RawHTML = false
+	html := convert(t, &config.Config{Markdown: mdConfig}, "\"Hi\" ~~there~~ <em>you</em>\n")
+
+	assert.Equal(t, "<p>&quot;Hi&quot; ~~there~~ <!-- raw HTML omitted -->you<!-- raw HTML omitted --></p>\n", html)
+}
+
+func TestEnablingOptionalExtensions(t *testing.T) {
+	mdConfig := config.DefaultMarkdownConfig()
+	mdConfig.TaskLists = true
+	mdConfig.DefinitionLists = true
+	mdConfig.Linkify = true
+	html := convert(t, &config.Config{Markdown: mdConfig}, "- [x] done\n\nTerm\n: Definition\n\nSee https://example.com\n")
+
+	assert.Contains(t, html, `<li><input checked="" disabled="" type="checkbox" /> done</li>`)
+	assert.Contains(t, html, "<dl>\n<dt>Term</dt>\n<dd>Definition</dd>\n</dl>")
+	assert.Contains(t, html, `<a href="https://example.com">https://example.com</a>`)
+}
+
+func TestPunctuationFollowsSiteLanguage(t *testing.T) {
+	cfg := &config.Config{DefaultLanguage: "de", Markdown: config.DefaultMarkdownConfig()}
+	html := convert(t, cfg, "Er sagte \"Hallo\" -- und ging...\n")
+
+	assert.Equal(t, "<p>Er sagte „Hallo“ – und ging…</p>\n", html)
+}
+
+func TestPunctuationFollowsPageLanguage(t *testing.T) {
+	cfg := &config.Config{DefaultLanguage: "en", Markdown: config.DefaultMarkdownConfig()}
+	html := convert(t, cfg, "+++\nlanguage = \"fr-CA\"\n+++\n\nIl a dit \"bonjour\".\n")
+
+	assert.Equal(t, "<p>Il a dit «\u00a0bonjour\u00a0».</p>\n", html)
+}
+
+func TestPunctuationOverridesFromConfig(t *testing.T) {
+	mdConfig := config.DefaultMarkdownConfig()
+	mdConfig.Punctuation = map[string]config.PunctuationConfig{
+		"fr": {LeftDoubleQuote: "«\u202f", RightDoubleQuote: "\u202f»"},
+	}
+	cfg := &config.Config{DefaultLanguage: "fr", Markdown: mdConfig}
+	html := convert(t, cfg, "Il a dit \"bonjour\" -- 'oui'.\n")
+
+	assert.Equal(t, "<p>Il a dit «\u202fbonjour\u202f» – “oui”.</p>\n", html)
+}
@@ -0,0 +1,193 @@
+package markdown
+
+import (
+	"strings"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"go.abhg.dev/goldmark/frontmatter"
+)
+
+const nbsp = "\u00a0"
+
+// languagePunctuation lists the substitutions that differ from the English
+// defaults, keyed by lowercase language tag.
+var languagePunctuation = map[string]map[TypographicPunctuation]string{
+	"cs": {
+		LeftDoubleQuote:  "„",
+		RightDoubleQuote: "“",
+		LeftSingleQuote:  "‚",
+		RightSingleQuote: "‘",
+	},
+	"da": {
+		LeftDoubleQuote:  "»",
+		RightDoubleQuote: "«",
+		LeftSingleQuote:  "›",
+		RightSingleQuote: "‹",
+	},
+	"de": {
+		LeftDoubleQuote:  "„",
+		RightDoubleQuote: "“",
+		LeftSingleQuote:  "‚",
+		RightSingleQuote: "‘",
+	},
+	"de-ch": {
+		LeftDoubleQuote:  "«",
+		RightDoubleQuote: "»",
+		LeftSingleQuote:  "‹",
+		RightSingleQuote: "›",
+	},
+	"es": {
+		LeftDoubleQuote:  "«",
+		RightDoubleQuote: "»",
+	},
+	"fr": {
+		LeftDoubleQuote:  "«" + nbsp,
+		RightDoubleQuote: nbsp + "»",
+		LeftSingleQuote:  "“",
+		RightSingleQuote: "”",
+		LeftAngleQuote:   "«" + nbsp,
+		RightAngleQuote:  nbsp + "»",
+	},
+	"it": {
+		LeftDoubleQuote:  "«",
+		RightDoubleQuote: "»",
+	},
+	"ja": {
+		LeftDoubleQuote:  "「",
+		RightDoubleQuote: "」",
+		LeftSingleQuote:  "『",
+		RightSingleQuote: "』",
+	},
+	"pt": {
+		LeftDoubleQuote:  "«",
+		RightDoubleQuote: "»",
+	},
+	"pt-br": {},
+	"ru": {
+		LeftDoubleQuote:  "«",
+		RightDoubleQuote: "»",
+		LeftSingleQuote:  "„",
+		RightSingleQuote: "“",
+	},
+	"sv": {
+		LeftDoubleQuote:  "”",
+		RightDoubleQuote: "”",
+		LeftSingleQuote:  "’",
+		RightSingleQuote: "’",
+	},
+}
+
+func normalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// lookupLanguage finds the entry for lang, falling back to its base language
+// (e.g. "fr" for "fr-CA").
+func lookupLanguage[T any](languages map[string]T, lang string) T {
+	lang = normalizeLanguage(lang)
+	if value, ok := languages[lang]; ok {
+		return value
+	}
+
+	if base, _, found := strings.Cut(lang, "-"); found {
+		return languages[base]
+	}
+
+	var zero T
+	return zero
+}
+
+type languageFrontMatter struct {
+	Language string `toml:"language" yaml:"language"`
+}
+
+var pageLanguageKey = parser.NewContextKey()
+
+// SetPageLanguage records the language of the page to be parsed. Use it when
+// the page's front matter is not parsed by goldmark.
+func SetPageLanguage(pc parser.Context, lang string) {
+	pc.Set(pageLanguageKey, lang)
+}
+
+// pageLanguage returns the language declared in the page's front matter or
+// the fallback if there is none.
+func pageLanguage(pc parser.Context, fallback string) string {
+	if lang, ok := pc.Get(pageLanguageKey).(string); ok && lang != "" {
+		return lang
+	}
+
+	fm := languageFrontMatter{}
+	if data := frontmatter.Get(pc); data != nil {
+		// Decoding errors are reported when the page itself is decoded
+		_ = data.Decode(&fm)
+	}
+
+	if fm.Language != "" {
+		return fm.Language
+	}
+
+	return fallback
+}
+
+func punctuationOverrides(p config.PunctuationConfig) map[TypographicPunctuation]string {
+	overrides := map[TypographicPunctuation]string{
+		LeftSingleQuote:  p.LeftSingleQuote,
+		RightSingleQuote: p.RightSingleQuote,
+		LeftDoubleQuote:  p.LeftDoubleQuote,
+		RightDoubleQuote: p.RightDoubleQuote,
+		EnDash:           p.EnDash,
+		EmDash:           p.EmDash,
+		Ellipsis:         p.Ellipsis,
+		LeftAngleQuote:   p.LeftAngleQuote,
+		RightAngleQuote:  p.RightAngleQuote,
+		Apostrophe:       p.Apostrophe,
+	}
+
+	for k, v := range overrides {
+		if v == "" {
+			delete(overrides, k)
+		}
+	}
+
+	return overrides
+}
+
+func substitutionTable(values ...map[TypographicPunctuation]string) [][]byte {
+	table := newDefaultSubstitutions()
+	for _, value := range values {
+		for k, v := range value {
+			table[k] = []byte(v)
+		}
+	}
+
+	return table
+}
+
+// languageSubstitutions builds the substitution tables for the built-in
+// languages and the ones overridden under [markdown.punctuation.<language>].
+func languageSubstitutions(overrides map[string]config.PunctuationConfig) map[string][][]byte {
+	tables := make(map[string][][]byte)
+	for lang, punctuation := range languagePunctuation {
+		tables[lang] = substitutionTable(punctuation)
+	}
+
+	for lang, override := range overrides {
+		tables[normalizeLanguage(lang)] = substitutionTable(
+			lookupLanguage(languagePunctuation, lang),
+			punctuationOverrides(override),
+		)
+	}
+
+	return tables
+}
+
+// LocalizedTypographer returns a Typographer that substitutes punctuation
+// according to the language of each page. Pages without a language use the
+// site's default language.
+func LocalizedTypographer(cfg *config.Config) goldmark.Extender {
+	return NewTypographer(
+		WithLanguages(cfg.DefaultLanguage, languageSubstitutions(cfg.Markdown.Punctuation)),
+	)
+}
@@ -0,0 +1,164 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ReferencePrefix marks a link destination as the source path of another
+// page, e.g. [see day 1](@/posts/day-1.md).
+const ReferencePrefix = "@/"
+
+// referenceTitle is left in place of the text of a wiki link without a label
+// until the title of the page it links to is known.
+const referenceTitle = "<!--assg:title %s-->"
+
+var referenceRegex = regexp.MustCompile(`(href="|<!--assg:title )@/([^"#<>\s]*)(#[^"<>\s]*)?("|-->)`)
+
+// PageReference is what a reference to a page's source path resolves to.
+type PageReference struct {
+	RootPath string
+	Title    string
+}
+
+// ResolveReferences replaces the page references in rendered HTML using
+// resolve, which is given the source path of the linked page. The source
+// paths that could not be resolved are returned and left as they are.
+func ResolveReferences(
+	htmlContent string,
+	resolve func(sourcePath string) (PageReference, bool),
+) (string, []string) {
+	missing := []string{}
+	resolved := referenceRegex.ReplaceAllStringFunc(htmlContent, func(match string) string {
+		parts := referenceRegex.FindStringSubmatch(match)
+		sourcePath, err := url.PathUnescape(parts[2])
+		if err != nil {
+			sourcePath = parts[2]
+		}
+
+		ref, ok := resolve(path.Clean(sourcePath))
+		if !ok {
+			missing = append(missing, sourcePath)
+			if parts[1] == "href=\"" {
+				return match
+			}
+
+			return string(util.EscapeHTML([]byte(sourcePath)))
+		}
+
+		if parts[1] == "href=\"" {
+			href := util.EscapeHTML(util.URLEscape([]byte(ref.RootPath), false))
+			return parts[1] + string(href) + parts[3] + parts[4]
+		}
+
+		return string(util.EscapeHTML([]byte(ref.Title)))
+	})
+
+	return resolved, missing
+}
+
+// KindWikiLink is the NodeKind of WikiLink nodes.
+var KindWikiLink = gast.NewNodeKind("WikiLink")
+
+// WikiLink is a [[path]] or [[path|label]] link to another page by its source
+// path.
+type WikiLink struct {
+	gast.BaseInline
+	Target []byte
+	Label  []byte
+}
+
+func (n *WikiLink) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{
+		"Target": string(n.Target),
+		"Label":  string(n.Label),
+	}, nil)
+}
+
+func (n *WikiLink) Kind() gast.NodeKind {
+	return KindWikiLink
+}
+
+type wikiLinkParser struct{}
+
+func (p *wikiLinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func (p *wikiLinkParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, _ := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte("[[")) {
+		return nil
+	}
+
+	end := bytes.Index(line, []byte("]]"))
+	if end < 0 {
+		return nil
+	}
+
+	inner := line[2:end]
+	if len(bytes.TrimSpace(inner)) == 0 || bytes.ContainsAny(inner, "[]") {
+		return nil
+	}
+
+	target, label, _ := bytes.Cut(inner, []byte("|"))
+	block.Advance(end + 2)
+
+	return &WikiLink{
+		Target: bytes.TrimSpace(target),
+		Label:  bytes.TrimSpace(label),
+	}
+}
+
+type wikiLinkRenderer struct{}
+
+func (r *wikiLinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindWikiLink, r.render)
+}
+
+func (r *wikiLinkRenderer) render(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	link := n.(*WikiLink)
+	target := ReferencePrefix + strings.TrimPrefix(string(link.Target), "/")
+	_, _ = w.WriteString(`<a href="`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(target), false)))
+	_, _ = w.WriteString(`">`)
+	if len(link.Label) > 0 {
+		_, _ = w.Write(util.EscapeHTML(link.Label))
+	} else {
+		sourcePath, _, _ := strings.Cut(target, "#")
+		_, _ = fmt.Fprintf(w, referenceTitle, util.URLEscape([]byte(sourcePath), false))
+	}
+	_, _ = w.WriteString("</a>")
+
+	return gast.WalkContinue, nil
+}
+
+type wikiLinks struct{}
+
+// WikiLinks is an extension for [[path]] and [[path|label]] links to other
+// pages by their source path.
+var WikiLinks = &wikiLinks{}
+
+func (e *wikiLinks) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(&wikiLinkParser{}, 199),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&wikiLinkRenderer{}, 500),
+	))
+}
@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"bytes"
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func renderReferences(t *testing.T, source string) string {
+	md := newMarkdown(t, &config.Config{Markdown: config.DefaultMarkdownConfig()})
+	var buf bytes.Buffer
+	assert.NoError(t, md.Convert([]byte(source), &buf))
+
+	return buf.String()
+}
+
+func TestReferenceLinksAreKept(t *testing.T) {
+	assert.Equal(
+		t,
+		"<p><a href=\"@/posts/day-1.md#morning\">see day 1</a></p>\n",
+		renderReferences(t, "[see day 1](@/posts/day-1.md#morning)\n"),
+	)
+}
+
+func TestWikiLinks(t *testing.T) {
+	assert.Equal(
+		t,
+		"<p>Read <a href=\"@/posts/day-1\"><!--assg:title @/posts/day-1--></a> and "+
+			"<a href=\"@/posts/day%202.md#end\">the &lt;second&gt; day</a>, not [[]].</p>\n",
+		renderReferences(t, "Read [[posts/day-1]] and [[posts/day 2.md#end|the <second> day]], not [[]].\n"),
+	)
+}
+
+func TestResolveReferences(t *testing.T) {
+	pages := map[string]PageReference{
+		"posts/day-1.md":        {RootPath: "/posts/day-1/", Title: "Day <1>"},
+		"posts/day 2.md":        {RootPath: "/posts/day-2/", Title: "Day 2"},
+		"posts/day-3.md":        {RootPath: "/posts/day-3/", Title: "Day 3"},
+		"posts/bundle/index.md": {RootPath: "/posts/bundle/", Title: "Bundle"},
+	}
+	resolve := func(sourcePath string) (PageReference, bool) {
+		ref, ok := pages[sourcePath]
+		return ref, ok
+	}
+
+	html := renderReferences(
+		t,
+		"[[posts/day-1.md]] [two](@/posts/day%202.md#end) [[posts/bundle/index.md|b]] [gone](@/posts/gone.md) [[posts/missing.md]]\n",
+	)
+	resolved, missing := ResolveReferences(html, resolve)
+
+	assert.Equal(
+		t,
+		"<p><a href=\"/posts/day-1/\">Day &lt;1&gt;</a> <a href=\"/posts/day-2/#end\">two</a> "+
+			"<a href=\"/posts/bundle/\">b</a> <a href=\"@/posts/gone.md\">gone</a> "+
+			"<a href=\"@/posts/missing.md\">posts/missing.md</a></p>\n",
+		resolved,
+	)
+	assert.Equal(t, []string{"posts/gone.md", "posts/missing.md", "posts/missing.md"}, missing)
+}
@@ -0,0 +1,508 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ShortcodeDir is the directory under templates where shortcode templates
+// are looked up.
+const ShortcodeDir = "shortcodes"
+
+// ShortcodeRenderer renders shortcode templates. It is implemented by
+// template.Engine.
+type ShortcodeRenderer interface {
+	TemplateExists(name string) bool
+	RenderPartial(name string, result io.Writer, data interface{}) error
+}
+
+// ShortcodeContent is the data passed to shortcode templates.
+type ShortcodeContent struct {
+	Name string
+	// Args holds the positional arguments, e.g. "abc" in {{< youtube "abc" >}}
+	Args []string
+	// Params holds the named arguments, e.g. id in {{< youtube id="abc" >}}
+	Params map[string]string
+	// Inner is the Markdown between the opening and closing tags rendered
+	// as HTML.
+	Inner template.HTML
+}
+
+// Get returns the named parameter or an empty string if it was not given.
+func (s ShortcodeContent) Get(key string) string {
+	return s.Params[key]
+}
+
+// shortcodeTag matches {{< name args >}}, {{< name args />}}, {{< /name >}}
+// and the escaped form {{</* name args */>}}.
+var shortcodeTag = regexp.MustCompile(
+	`\{\{<(/\*)?\s*(/)?([A-Za-z0-9_-]+)((?:\s+.*?)?)\s*(/)?(\*/)?>\}\}`,
+)
+
+var shortcodeArg = regexp.MustCompile(
+	`([A-Za-z0-9_-]+)=(?:"([^"]*)"|'([^']*)'|(\S+))|"([^"]*)"|'([^']*)'|(\S+)`,
+)
+
+var frontMatterBlock = regexp.MustCompile(`(?s)^(\+{3,}|-{3,})\r?\n.*?\r?\n(\+{3,}|-{3,})\r?\n`)
+
+type shortcodeMarkdown struct {
+	goldmark.Markdown
+	renderer ShortcodeRenderer
+}
+
+// WithShortcodes wraps md so that shortcodes in the Markdown source are
+// rendered with the templates in the shortcodes directory.
+func WithShortcodes(md goldmark.Markdown, templates ShortcodeRenderer) goldmark.Markdown {
+	md.Parser().AddOptions(
+		parser.WithInlineParsers(util.Prioritized(&shortcodeParser{}, 100)),
+		parser.WithASTTransformers(util.Prioritized(&shortcodeBlockTransformer{}, 100)),
+	)
+	md.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&shortcodeRenderer{}, 100)))
+
+	return &shortcodeMarkdown{Markdown: md, renderer: templates}
+}
+
+type shortcodeTagMatch struct {
+	start, end  int
+	escaped     bool
+	closing     bool
+	selfClosing bool
+	inCode      bool
+	name        string
+	args        string
+}
+
+func matchTag(source string, loc []int) shortcodeTagMatch {
+	group := func(i int) string {
+		if loc[2*i] < 0 {
+			return ""
+		}
+		return source[loc[2*i]:loc[2*i+1]]
+	}
+
+	return shortcodeTagMatch{
+		start:       loc[0],
+		end:         loc[1],
+		escaped:     group(1) != "" && group(6) != "",
+		closing:     group(2) != "",
+		name:        group(3),
+		args:        strings.TrimSpace(group(4)),
+		selfClosing: group(5) != "",
+	}
+}
+
+func parseShortcodeArgs(args string) ([]string, map[string]string) {
+	positional := []string{}
+	named := make(map[string]string)
+	for _, m := range shortcodeArg.FindAllStringSubmatch(args, -1) {
+		if m[1] != "" {
+			named[m[1]] = m[2] + m[3] + m[4]
+		} else {
+			positional = append(positional, m[5]+m[6]+m[7])
+		}
+	}
+
+	return positional, named
+}
+
+// A shortcode is replaced by a placeholder before the Markdown is parsed. The
+// placeholder starts with "{" so that shortcodeParser is triggered, holds the
+// index of the rendered shortcode in private use characters and then the text
+// of the rendered shortcode. Heading IDs are generated from the source, so
+// they get the text of the shortcode and nothing of the placeholder.
+const (
+	placeholderStart = "{\uE000"
+	placeholderText  = "\uE001"
+	placeholderEnd   = "\uE002}"
+	placeholderDigit = '\uE010'
+)
+
+// placeholderRegex matches the placeholders left in raw HTML, where the
+// Markdown is not parsed.
+var placeholderRegex = regexp.MustCompile(`\{\x{E000}([\x{E010}-\x{E019}]+)\x{E001}[^\x{E002}]*\x{E002}\}`)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+func placeholder(i int, html string) string {
+	var sb strings.Builder
+	sb.WriteString(placeholderStart)
+	for _, digit := range strconv.Itoa(i) {
+		sb.WriteRune(placeholderDigit + digit - '0')
+	}
+	sb.WriteString(placeholderText)
+	// the text must stay on one line and must not end the placeholder
+	text := strings.ReplaceAll(htmlTag.ReplaceAllString(html, " "), placeholderEnd, "")
+	sb.WriteString(strings.Join(strings.Fields(text), " "))
+	sb.WriteString(placeholderEnd)
+
+	return sb.String()
+}
+
+func placeholderIndex(digits string) int {
+	i := 0
+	for _, digit := range digits {
+		i = i*10 + int(digit-placeholderDigit)
+	}
+
+	return i
+}
+
+var shortcodesKey = parser.NewContextKey()
+
+func (s *shortcodeMarkdown) Convert(source []byte, w io.Writer, opts ...parser.ParseOption) error {
+	frontMatter := frontMatterBlock.Find(source)
+	body := string(source[len(frontMatter):])
+	if !strings.Contains(body, "{{<") {
+		return s.Markdown.Convert(source, w, opts...)
+	}
+
+	pc := parseContext(opts)
+	if pc == nil {
+		pc = parser.NewContext()
+		opts = append(opts, parser.WithContext(pc))
+	}
+
+	expansion := &shortcodeExpansion{
+		md:          s,
+		frontMatter: frontMatter,
+		pc:          pc,
+		code:        s.codeRanges(body),
+	}
+	expanded, err := expansion.expand(body)
+	if err != nil {
+		return err
+	}
+
+	pc.Set(shortcodesKey, expansion.rendered)
+	var buf bytes.Buffer
+	err = s.Markdown.Convert(append(frontMatter, expanded...), &buf, opts...)
+	if err != nil {
+		return err
+	}
+
+	output := placeholderRegex.ReplaceAllStringFunc(buf.String(), func(match string) string {
+		digits := placeholderRegex.FindStringSubmatch(match)[1]
+		return expansion.rendered[placeholderIndex(digits)]
+	})
+	_, err = io.WriteString(w, output)
+
+	return err
+}
+
+// parseContext returns the parser context given in opts, or nil.
+func parseContext(opts []parser.ParseOption) parser.Context {
+	config := &parser.ParseConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config.Context
+}
+
+// codeRanges returns the start and end of the code spans and code blocks in
+// source. Shortcodes in them are shown as they are.
+func (s *shortcodeMarkdown) codeRanges(source string) [][2]int {
+	ranges := [][2]int{}
+	doc := s.Markdown.Parser().Parse(text.NewReader([]byte(source)), parser.WithContext(parser.NewContext()))
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			for i := 0; i < n.Lines().Len(); i++ {
+				line := n.Lines().At(i)
+				ranges = append(ranges, [2]int{line.Start, line.Stop})
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeSpan:
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					ranges = append(ranges, [2]int{t.Segment.Start, t.Segment.Stop})
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return ranges
+}
+
+// shortcodeExpansion holds the state of the expansion of the shortcodes of a
+// document.
+type shortcodeExpansion struct {
+	md          *shortcodeMarkdown
+	frontMatter []byte
+	pc          parser.Context
+	code        [][2]int
+	rendered    []string
+}
+
+func (e *shortcodeExpansion) inCode(pos int) bool {
+	for _, r := range e.code {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expand replaces the shortcodes in source with placeholders and appends
+// their rendered output to rendered.
+func (e *shortcodeExpansion) expand(source string) (string, error) {
+	var sb strings.Builder
+	locs := shortcodeTag.FindAllStringSubmatchIndex(source, -1)
+	tags := make([]shortcodeTagMatch, len(locs))
+	for i, loc := range locs {
+		tags[i] = matchTag(source, loc)
+		tags[i].inCode = e.inCode(tags[i].start)
+	}
+
+	pos := 0
+	for i := 0; i < len(tags); i++ {
+		tag := tags[i]
+		if tag.start < pos || (tag.inCode && !tag.escaped) {
+			continue
+		}
+		sb.WriteString(source[pos:tag.start])
+		pos = tag.end
+
+		if tag.escaped {
+			sb.WriteString(strings.Replace(strings.Replace(source[tag.start:tag.end], "{{</*", "{{<", 1), "*/>}}", ">}}", 1))
+			continue
+		}
+
+		if tag.closing {
+			return "", fmt.Errorf("unexpected closing shortcode \"%s\"", tag.name)
+		}
+
+		inner := ""
+		hasInner := false
+		if !tag.selfClosing {
+			if closeIndex := findClosingTag(tags, i, tag.name); closeIndex > 0 {
+				closeTag := tags[closeIndex]
+				inner = source[tag.end:closeTag.start]
+				hasInner = true
+				pos = closeTag.end
+				i = closeIndex
+			}
+		}
+
+		html, err := e.render(tag, inner, hasInner)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(placeholder(len(e.rendered), html))
+		e.rendered = append(e.rendered, html)
+	}
+	sb.WriteString(source[pos:])
+
+	return sb.String(), nil
+}
+
+func findClosingTag(tags []shortcodeTagMatch, openIndex int, name string) int {
+	depth := 0
+	for i := openIndex + 1; i < len(tags); i++ {
+		tag := tags[i]
+		if tag.escaped || tag.inCode || tag.name != name {
+			continue
+		}
+
+		if tag.closing {
+			if depth == 0 {
+				return i
+			}
+			depth--
+		} else if !tag.selfClosing {
+			depth++
+		}
+	}
+
+	return -1
+}
+
+func (e *shortcodeExpansion) render(tag shortcodeTagMatch, inner string, hasInner bool) (string, error) {
+	s := e.md
+	templateName := ShortcodeDir + "/" + tag.name + ".html"
+	if s.renderer == nil || !s.renderer.TemplateExists(templateName) {
+		return "", fmt.Errorf("shortcode \"%s\" has no template %s", tag.name, templateName)
+	}
+
+	args, params := parseShortcodeArgs(tag.args)
+	data := ShortcodeContent{
+		Name:   tag.name,
+		Args:   args,
+		Params: params,
+	}
+
+	if hasInner {
+		// the inner content is a part of the page, so it has the page's front
+		// matter, language and base path
+		innerContext := parser.NewContext()
+		for _, key := range []parser.ContextKey{pageLanguageKey, basePathKey} {
+			if value := e.pc.Get(key); value != nil {
+				innerContext.Set(key, value)
+			}
+		}
+
+		var buf bytes.Buffer
+		source := append(append([]byte{}, e.frontMatter...), inner...)
+		if err := s.Convert(source, &buf, parser.WithContext(innerContext)); err != nil {
+			return "", err
+		}
+		data.Inner = template.HTML(unwrapParagraph(buf.String()))
+	}
+
+	var buf bytes.Buffer
+	if err := s.renderer.RenderPartial(templateName, &buf, data); err != nil {
+		return "", fmt.Errorf("shortcode \"%s\": %v", tag.name, err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// unwrapParagraph removes the <p> tags around inner content that is a single
+// paragraph so that inline shortcodes stay inline.
+func unwrapParagraph(html string) string {
+	html = strings.TrimSpace(html)
+	if strings.HasPrefix(html, "<p>") && strings.HasSuffix(html, "</p>") &&
+		strings.Count(html, "<p>") == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(html, "<p>"), "</p>")
+	}
+
+	return html
+}
+
+// KindShortcode is the NodeKind of Shortcode nodes.
+var KindShortcode = ast.NewNodeKind("Shortcode")
+
+// Shortcode is a rendered shortcode in a paragraph or a heading. Its child is
+// the text of the rendered shortcode, used for the heading's text.
+type Shortcode struct {
+	ast.BaseInline
+	HTML string
+}
+
+func (n *Shortcode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"HTML": n.HTML}, nil)
+}
+
+func (n *Shortcode) Kind() ast.NodeKind {
+	return KindShortcode
+}
+
+// KindShortcodeBlock is the NodeKind of ShortcodeBlock nodes.
+var KindShortcodeBlock = ast.NewNodeKind("ShortcodeBlock")
+
+// ShortcodeBlock is a rendered shortcode on its own line, which replaces its
+// paragraph.
+type ShortcodeBlock struct {
+	ast.BaseBlock
+	HTML string
+}
+
+func (n *ShortcodeBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"HTML": n.HTML}, nil)
+}
+
+func (n *ShortcodeBlock) Kind() ast.NodeKind {
+	return KindShortcodeBlock
+}
+
+type shortcodeParser struct{}
+
+func (p *shortcodeParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (p *shortcodeParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte(placeholderStart)) {
+		return nil
+	}
+
+	end := bytes.Index(line, []byte(placeholderEnd))
+	textStart := bytes.Index(line, []byte(placeholderText))
+	rendered, _ := pc.Get(shortcodesKey).([]string)
+	if end < 0 || textStart < 0 || textStart > end {
+		return nil
+	}
+
+	i := placeholderIndex(string(line[len(placeholderStart):textStart]))
+	if i >= len(rendered) {
+		return nil
+	}
+
+	node := &Shortcode{HTML: rendered[i]}
+	textStart += len(placeholderText)
+	if textStart < end {
+		node.AppendChild(node, ast.NewTextSegment(text.NewSegment(segment.Start+textStart, segment.Start+end)))
+	}
+	block.Advance(end + len(placeholderEnd))
+
+	return node
+}
+
+// shortcodeBlockTransformer replaces the paragraphs that only have a shortcode
+// with the shortcode.
+type shortcodeBlockTransformer struct{}
+
+func (t *shortcodeBlockTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	paragraphs := []*ast.Paragraph{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if paragraph, ok := n.(*ast.Paragraph); ok && entering {
+			if paragraph.ChildCount() == 1 && paragraph.FirstChild().Kind() == KindShortcode {
+				paragraphs = append(paragraphs, paragraph)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, paragraph := range paragraphs {
+		shortcode := paragraph.FirstChild().(*Shortcode)
+		paragraph.Parent().ReplaceChild(paragraph.Parent(), paragraph, &ShortcodeBlock{HTML: shortcode.HTML})
+	}
+}
+
+type shortcodeRenderer struct{}
+
+func (r *shortcodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindShortcode, r.render)
+	reg.Register(KindShortcodeBlock, r.renderBlock)
+}
+
+func (r *shortcodeRenderer) render(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(n.(*Shortcode).HTML)
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *shortcodeRenderer) renderBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(n.(*ShortcodeBlock).HTML)
+		_ = w.WriteByte('\n')
+	}
+
+	return ast.WalkSkipChildren, nil
+}
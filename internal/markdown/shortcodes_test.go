@@ -0,0 +1,149 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark/parser"
+)
+
+type fakeShortcodeRenderer map[string]*template.Template
+
+func (f fakeShortcodeRenderer) TemplateExists(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+func (f fakeShortcodeRenderer) RenderPartial(name string, result io.Writer, data interface{}) error {
+	tmpl, ok := f[name]
+	if !ok {
+		return fmt.Errorf("template %s not found", name)
+	}
+
+	return tmpl.Execute(result, data)
+}
+
+var shortcodeTemplates = fakeShortcodeRenderer{
+	"shortcodes/youtube.html": template.Must(template.New("youtube").Parse(
+		`<iframe src="https://www.youtube.com/embed/{{ .Get "id" }}"></iframe>`,
+	)),
+	"shortcodes/kbd.html": template.Must(template.New("kbd").Parse(
+		`<kbd>{{ .Inner }}</kbd>`,
+	)),
+	"shortcodes/note.html": template.Must(template.New("note").Parse(
+		`<aside class="{{ index .Args 0 }}">{{ .Inner }}</aside>`,
+	)),
+}
+
+func convertWithShortcodes(source string) (string, error) {
+	md, err := New(&config.Config{Markdown: config.DefaultMarkdownConfig()})
+	if err != nil {
+		return "", err
+	}
+	md = WithShortcodes(md, shortcodeTemplates)
+	var buf bytes.Buffer
+	err = md.Convert([]byte(source), &buf)
+
+	return buf.String(), err
+}
+
+func TestStandaloneShortcode(t *testing.T) {
+	html, err := convertWithShortcodes("Intro.\n\n{{< youtube id=\"abc123\" >}}\n\nOutro.\n")
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"<p>Intro.</p>\n<iframe src=\"https://www.youtube.com/embed/abc123\"></iframe>\n<p>Outro.</p>\n",
+		html,
+	)
+}
+
+func TestInlineShortcodeWithInnerContent(t *testing.T) {
+	html, err := convertWithShortcodes("Press {{< kbd >}}**Ctrl**{{< /kbd >}} now.\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Press <kbd><strong>Ctrl</strong></kbd> now.</p>\n", html)
+}
+
+func TestBlockShortcodeWithNestedShortcode(t *testing.T) {
+	source := "{{< note \"warning\" >}}\nFirst.\n\nPress {{< kbd >}}Esc{{< /kbd >}}.\n{{< /note >}}\n"
+	html, err := convertWithShortcodes(source)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<aside class=\"warning\"><p>First.</p>\n<p>Press <kbd>Esc</kbd>.</p></aside>\n", html)
+}
+
+func TestEscapedShortcodeIsLeftAsText(t *testing.T) {
+	html, err := convertWithShortcodes("Use `{{</* youtube id=\"x\" */>}}` to embed.\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Use <code>{{&lt; youtube id=&quot;x&quot; &gt;}}</code> to embed.</p>\n", html)
+}
+
+func TestShortcodesInFrontMatterAreIgnored(t *testing.T) {
+	html, err := convertWithShortcodes("+++\ntitle = \"{{< youtube >}}\"\n+++\n\n{{< youtube id=\"a\" >}}\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<iframe src=\"https://www.youtube.com/embed/a\"></iframe>\n", html)
+}
+
+func TestUnknownShortcode(t *testing.T) {
+	_, err := convertWithShortcodes("{{< vimeo id=\"1\" >}}\n")
+
+	assert.ErrorContains(t, err, "shortcode \"vimeo\" has no template shortcodes/vimeo.html")
+}
+
+func TestShortcodeInHeading(t *testing.T) {
+	md, err := New(&config.Config{Markdown: config.DefaultMarkdownConfig()})
+	assert.NoError(t, err)
+	md = WithShortcodes(md, shortcodeTemplates)
+
+	pc := parser.NewContext()
+	var buf bytes.Buffer
+	err = md.Convert([]byte("## Press {{< kbd >}}Esc{{< /kbd >}} key\n"), &buf, parser.WithContext(pc))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<h2 id=\"press-esc-key\">Press <kbd>Esc</kbd> key</h2>\n", buf.String())
+	assert.Equal(t, []Heading{{Level: 2, Text: "Press Esc key", ID: "press-esc-key"}}, GetHeadings(pc))
+}
+
+func TestShortcodesInCodeAreLeftAsText(t *testing.T) {
+	html, err := convertWithShortcodes(
+		"Use `{{< kbd >}}Esc{{< /kbd >}}` like this:\n\n```\n{{< youtube id=\"x\" >}}\n```\n\n    {{< kbd >}}Tab{{< /kbd >}}\n",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"<p>Use <code>{{&lt; kbd &gt;}}Esc{{&lt; /kbd &gt;}}</code> like this:</p>\n"+
+			"<pre><code>{{&lt; youtube id=&quot;x&quot; &gt;}}\n</code></pre>\n"+
+			"<pre><code>{{&lt; kbd &gt;}}Tab{{&lt; /kbd &gt;}}\n</code></pre>\n",
+		html,
+	)
+}
+
+func TestShortcodeInnerContentHasThePageContext(t *testing.T) {
+	md, err := New(&config.Config{Markdown: config.DefaultMarkdownConfig()})
+	assert.NoError(t, err)
+	md = WithShortcodes(md, shortcodeTemplates)
+
+	pc := parser.NewContext()
+	SetPageLanguage(pc, "fr")
+	SetBasePath(pc, "/posts/day-1/")
+	source := "{{< note \"info\" >}}\nIl a dit \"bonjour\".\n\nSee ![Photo](photo.jpg).\n{{< /note >}}\n"
+	var buf bytes.Buffer
+	err = md.Convert([]byte(source), &buf, parser.WithContext(pc))
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"<aside class=\"info\"><p>Il a dit «\u00a0bonjour\u00a0».</p>\n"+
+			"<p>See <img src=\"/posts/day-1/photo.jpg\" alt=\"Photo\" />.</p></aside>\n",
+		buf.String(),
+	)
+}
@@ -0,0 +1,130 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Heading is an entry in a page's table of contents.
+type Heading struct {
+	Level    int
+	Text     string
+	ID       string
+	Children []*Heading
+}
+
+var headingsKey = parser.NewContextKey()
+
+// GetHeadings returns the headings collected while parsing a document in the
+// order they appear.
+func GetHeadings(pc parser.Context) []Heading {
+	headings, _ := pc.Get(headingsKey).([]Heading)
+	return headings
+}
+
+type headingCollector struct{}
+
+func (c *headingCollector) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	headings := []Heading{}
+	gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		heading, ok := n.(*gast.Heading)
+		if !ok || !entering {
+			return gast.WalkContinue, nil
+		}
+
+		id := ""
+		if value, ok := heading.AttributeString("id"); ok {
+			if b, ok := value.([]byte); ok {
+				id = string(b)
+			}
+		}
+
+		headings = append(headings, Heading{
+			Level: heading.Level,
+			Text:  string(heading.Text(reader.Source())),
+			ID:    id,
+		})
+
+		return gast.WalkSkipChildren, nil
+	})
+
+	pc.Set(headingsKey, headings)
+}
+
+type tableOfContents struct{}
+
+// TableOfContents is an extension that collects the headings of a document.
+// Use GetHeadings to retrieve them after parsing.
+var TableOfContents = &tableOfContents{}
+
+func (e *tableOfContents) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&headingCollector{}, 999),
+	))
+}
+
+// HeadingTree nests the headings between minLevel and maxLevel under their
+// closest preceding heading of a higher level.
+func HeadingTree(headings []Heading, minLevel, maxLevel int) []*Heading {
+	tree := []*Heading{}
+	stack := []*Heading{}
+	for _, heading := range headings {
+		if heading.Level < minLevel || heading.Level > maxLevel {
+			continue
+		}
+
+		node := &Heading{Level: heading.Level, Text: heading.Text, ID: heading.ID}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= node.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) == 0 {
+			tree = append(tree, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+	}
+
+	return tree
+}
+
+// FlattenHeadings lists the headings of a tree in document order.
+func FlattenHeadings(tree []*Heading) []Heading {
+	headings := []Heading{}
+	for _, node := range tree {
+		headings = append(headings, Heading{Level: node.Level, Text: node.Text, ID: node.ID})
+		headings = append(headings, FlattenHeadings(node.Children)...)
+	}
+
+	return headings
+}
+
+// RenderTableOfContents renders a heading tree as nested lists of links.
+func RenderTableOfContents(tree []*Heading) string {
+	if len(tree) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<ul>")
+	for _, node := range tree {
+		sb.WriteString(`<li><a href="#`)
+		sb.WriteString(html.EscapeString(node.ID))
+		sb.WriteString(`">`)
+		sb.WriteString(html.EscapeString(node.Text))
+		sb.WriteString("</a>")
+		sb.WriteString(RenderTableOfContents(node.Children))
+		sb.WriteString("</li>")
+	}
+	sb.WriteString("</ul>")
+
+	return sb.String()
+}
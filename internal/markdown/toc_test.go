@@ -0,0 +1,73 @@
+package markdown
+
+import (
+	"bytes"
+	"testing"
+
+	"codeberg.org/asartalo/assg/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark/parser"
+)
+
+const tocSource = `# Title
+
+## Getting "Started"
+
+### Install
+
+### Configure
+
+## Usage
+
+#### Deep
+`
+
+func TestHeadingsAreCollected(t *testing.T) {
+	md := newMarkdown(t, &config.Config{Markdown: config.DefaultMarkdownConfig()})
+	pc := parser.NewContext()
+	err := md.Convert([]byte(tocSource), &bytes.Buffer{}, parser.WithContext(pc))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Heading{
+		{Level: 1, Text: "Title", ID: "title"},
+		{Level: 2, Text: "Getting “Started”", ID: "getting-started"},
+		{Level: 3, Text: "Install", ID: "install"},
+		{Level: 3, Text: "Configure", ID: "configure"},
+		{Level: 2, Text: "Usage", ID: "usage"},
+		{Level: 4, Text: "Deep", ID: "deep"},
+	}, GetHeadings(pc))
+}
+
+func TestHeadingTree(t *testing.T) {
+	headings := []Heading{
+		{Level: 1, Text: "Title", ID: "title"},
+		{Level: 2, Text: "A", ID: "a"},
+		{Level: 3, Text: "A.1", ID: "a-1"},
+		{Level: 2, Text: "B", ID: "b"},
+		{Level: 4, Text: "B.1.1", ID: "b-1-1"},
+	}
+
+	tree := HeadingTree(headings, 2, 3)
+
+	assert.Equal(t, []*Heading{
+		{Level: 2, Text: "A", ID: "a", Children: []*Heading{
+			{Level: 3, Text: "A.1", ID: "a-1"},
+		}},
+		{Level: 2, Text: "B", ID: "b"},
+	}, tree)
+	assert.Equal(t, headings[1:4], FlattenHeadings(tree))
+}
+
+func TestRenderTableOfContents(t *testing.T) {
+	tree := HeadingTree([]Heading{
+		{Level: 2, Text: "A & B", ID: "a-b"},
+		{Level: 3, Text: "C", ID: "c"},
+	}, 1, 6)
+
+	assert.Equal(
+		t,
+		`<ul><li><a href="#a-b">A &amp; B</a><ul><li><a href="#c">C</a></li></ul></li></ul>`,
+		RenderTableOfContents(tree),
+	)
+	assert.Equal(t, "", RenderTableOfContents(nil))
+}
@@ -89,6 +89,11 @@ const (
 // Typographer extension.
 type TypographerConfig struct {
 	Substitutions [][]byte
+	// Languages holds substitutions by lowercase language tag. When set, the
+	// substitutions are chosen from the language of the page being parsed.
+	Languages map[string][][]byte
+	// DefaultLanguage is used for pages that do not declare a language.
+	DefaultLanguage string
 }
 
 func newDefaultSubstitutions() [][]byte {
@@ -138,6 +143,24 @@ func (o *withTypographicSubstitutions) SetTypographerOption(p *TypographerConfig
 	p.Substitutions = o.value
 }
 
+type withLanguages struct {
+	defaultLanguage string
+	languages       map[string][][]byte
+}
+
+func (o *withLanguages) SetParserOption(c *parser.Config) {}
+
+func (o *withLanguages) SetTypographerOption(p *TypographerConfig) {
+	p.Languages = o.languages
+	p.DefaultLanguage = o.defaultLanguage
+}
+
+// WithLanguages is a functional option that picks the substitutions from the
+// page's language, falling back to defaultLanguage.
+func WithLanguages(defaultLanguage string, languages map[string][][]byte) TypographerOption {
+	return &withLanguages{defaultLanguage, languages}
+}
+
 // WithTypographicSubstitutions is a functional otpion that specify replacement text
 // for punctuations.
 func WithTypographicSubstitutions[T []byte | string](values map[TypographicPunctuation]T) TypographerOption {
@@ -184,6 +207,26 @@ func NewTypographerParser(opts ...TypographerOption) parser.InlineParser {
 	return p
 }
 
+var substitutionsKey = parser.NewContextKey()
+
+func (s *typographerParser) substitutions(pc parser.Context) [][]byte {
+	if s.Languages == nil {
+		return s.Substitutions
+	}
+
+	if v := pc.Get(substitutionsKey); v != nil {
+		return v.([][]byte)
+	}
+
+	subs := lookupLanguage(s.Languages, pageLanguage(pc, s.DefaultLanguage))
+	if subs == nil {
+		subs = s.Substitutions
+	}
+	pc.Set(substitutionsKey, subs)
+
+	return subs
+}
+
 func (s *typographerParser) Trigger() []byte {
 	return []byte{'\'', '"', '-', '.', ',', '<', '>', '*', '['}
 }
@@ -191,17 +234,18 @@ func (s *typographerParser) Trigger() []byte {
 func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
 	line, _ := block.PeekLine()
 	c := line[0]
+	subs := s.substitutions(pc)
 	if len(line) > 2 {
 		if c == '-' {
-			if s.Substitutions[EmDash] != nil && line[1] == '-' && line[2] == '-' { // ---
-				node := gast.NewString(s.Substitutions[EmDash])
+			if subs[EmDash] != nil && line[1] == '-' && line[2] == '-' { // ---
+				node := gast.NewString(subs[EmDash])
 				node.SetCode(true)
 				block.Advance(3)
 				return node
 			}
 		} else if c == '.' {
-			if s.Substitutions[Ellipsis] != nil && line[1] == '.' && line[2] == '.' { // ...
-				node := gast.NewString(s.Substitutions[Ellipsis])
+			if subs[Ellipsis] != nil && line[1] == '.' && line[2] == '.' { // ...
+				node := gast.NewString(subs[Ellipsis])
 				node.SetCode(true)
 				block.Advance(3)
 				return node
@@ -211,23 +255,23 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 	}
 	if len(line) > 1 {
 		if c == '<' {
-			if s.Substitutions[LeftAngleQuote] != nil && line[1] == '<' { // <<
-				node := gast.NewString(s.Substitutions[LeftAngleQuote])
+			if subs[LeftAngleQuote] != nil && line[1] == '<' { // <<
+				node := gast.NewString(subs[LeftAngleQuote])
 				node.SetCode(true)
 				block.Advance(2)
 				return node
 			}
 			return nil
 		} else if c == '>' {
-			if s.Substitutions[RightAngleQuote] != nil && line[1] == '>' { // >>
-				node := gast.NewString(s.Substitutions[RightAngleQuote])
+			if subs[RightAngleQuote] != nil && line[1] == '>' { // >>
+				node := gast.NewString(subs[RightAngleQuote])
 				node.SetCode(true)
 				block.Advance(2)
 				return node
 			}
 			return nil
-		} else if s.Substitutions[EnDash] != nil && c == '-' && line[1] == '-' { // --
-			node := gast.NewString(s.Substitutions[EnDash])
+		} else if subs[EnDash] != nil && c == '-' && line[1] == '-' { // --
+			node := gast.NewString(subs[EnDash])
 			node.SetCode(true)
 			block.Advance(2)
 			return node
@@ -241,7 +285,7 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 		}
 		counter := getUnclosedCounter(pc)
 		if c == '\'' {
-			if s.Substitutions[Apostrophe] != nil {
+			if subs[Apostrophe] != nil {
 				// Handle decade abbrevations such as '90s
 				if d.CanOpen && !d.CanClose && len(line) > 3 &&
 					util.IsNumeric(line[1]) && util.IsNumeric(line[2]) && line[3] == 's' {
@@ -250,7 +294,7 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 						after = util.ToRune(line, 4)
 					}
 					if len(line) == 3 || util.IsSpaceRune(after) || util.IsPunctRune(after) {
-						node := gast.NewString(s.Substitutions[Apostrophe])
+						node := gast.NewString(subs[Apostrophe])
 						node.SetCode(true)
 						block.Advance(1)
 						return node
@@ -259,7 +303,7 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 				// special cases: 'twas, 'em, 'net
 				if len(line) > 1 && (unicode.IsPunct(before) || unicode.IsSpace(before)) &&
 					(line[1] == 't' || line[1] == 'e' || line[1] == 'n' || line[1] == 'l') {
-					node := gast.NewString(s.Substitutions[Apostrophe])
+					node := gast.NewString(subs[Apostrophe])
 					node.SetCode(true)
 					block.Advance(1)
 					return node
@@ -268,13 +312,13 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 				// converts any apostrophe in between two alphanumerics.
 				if len(line) > 1 && (unicode.IsDigit(before) || unicode.IsLetter(before)) &&
 					(unicode.IsLetter(util.ToRune(line, 1))) {
-					node := gast.NewString(s.Substitutions[Apostrophe])
+					node := gast.NewString(subs[Apostrophe])
 					node.SetCode(true)
 					block.Advance(1)
 					return node
 				}
 			}
-			if s.Substitutions[LeftSingleQuote] != nil && d.CanOpen && !d.CanClose {
+			if subs[LeftSingleQuote] != nil && d.CanOpen && !d.CanClose {
 				nt := LeftSingleQuote
 				// special cases: Alice's, I'm, Don't, You'd
 				if len(line) > 1 && (line[1] == 's' || line[1] == 'm' || line[1] == 't' || line[1] == 'd') &&
@@ -291,27 +335,27 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 					counter.Single++
 				}
 
-				node := gast.NewString(s.Substitutions[nt])
+				node := gast.NewString(subs[nt])
 				node.SetCode(true)
 				block.Advance(1)
 				return node
 			}
-			if s.Substitutions[RightSingleQuote] != nil {
+			if subs[RightSingleQuote] != nil {
 				// plural possesive and abbreviations: Smiths', doin'
 				if len(line) > 1 && unicode.IsSpace(util.ToRune(line, 0)) || unicode.IsPunct(util.ToRune(line, 0)) &&
 					(len(line) > 2 && !unicode.IsDigit(util.ToRune(line, 1))) {
-					node := gast.NewString(s.Substitutions[RightSingleQuote])
+					node := gast.NewString(subs[RightSingleQuote])
 					node.SetCode(true)
 					block.Advance(1)
 					return node
 				}
 			}
-			if s.Substitutions[RightSingleQuote] != nil && counter.Single > 0 {
+			if subs[RightSingleQuote] != nil && counter.Single > 0 {
 				isClose := d.CanClose && !d.CanOpen
 				maybeClose := d.CanClose && d.CanOpen && len(line) > 1 && unicode.IsPunct(util.ToRune(line, 1)) &&
 					(len(line) == 2 || (len(line) > 2 && util.IsPunct(line[2]) || util.IsSpace(line[2])))
 				if isClose || maybeClose {
-					node := gast.NewString(s.Substitutions[RightSingleQuote])
+					node := gast.NewString(subs[RightSingleQuote])
 					node.SetCode(true)
 					block.Advance(1)
 					counter.Single--
@@ -320,14 +364,14 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 			}
 		}
 		if c == '"' {
-			if s.Substitutions[LeftDoubleQuote] != nil && d.CanOpen && !d.CanClose {
-				node := gast.NewString(s.Substitutions[LeftDoubleQuote])
+			if subs[LeftDoubleQuote] != nil && d.CanOpen && !d.CanClose {
+				node := gast.NewString(subs[LeftDoubleQuote])
 				node.SetCode(true)
 				block.Advance(1)
 				counter.Double++
 				return node
 			}
-			if s.Substitutions[RightDoubleQuote] != nil && counter.Double > 0 {
+			if subs[RightDoubleQuote] != nil && counter.Double > 0 {
 				isClose := d.CanClose && !d.CanOpen
 				maybeClose := d.CanClose && d.CanOpen && len(line) > 1 && (unicode.IsPunct(util.ToRune(line, 1))) &&
 					(len(line) == 2 || (len(line) > 2 && util.IsPunct(line[2]) || util.IsSpace(line[2])))
@@ -336,7 +380,7 @@ func (s *typographerParser) Parse(parent gast.Node, block text.Reader, pc parser
 					if len(line) > 1 && line[1] == '"' && unicode.IsDigit(before) {
 						return nil
 					}
-					node := gast.NewString(s.Substitutions[RightDoubleQuote])
+					node := gast.NewString(subs[RightDoubleQuote])
 					node.SetCode(true)
 					block.Advance(1)
 					counter.Double--
@@ -37,8 +37,8 @@ type Server struct {
 	verbose bool
 }
 
-func NewServer(srcDir string, includeDrafts bool, verbose bool) (*Server, error) {
-	config, err := LoadServeConfiguration(srcDir, includeDrafts)
+func NewServer(srcDir string, includeDrafts bool, includeFuture bool, verbose bool) (*Server, error) {
+	config, err := LoadServeConfiguration(srcDir, includeDrafts, includeFuture)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +54,9 @@ func NewServer(srcDir string, includeDrafts bool, verbose bool) (*Server, error)
 func (s *Server) Start(ready chan bool) error {
 	srcDir := s.SrcDir
 	includeDrafts := s.Config.IncludeDrafts
+	includeFuture := s.Config.IncludeFuture
 
-	config, err := LoadServeConfiguration(srcDir, includeDrafts)
+	config, err := LoadServeConfiguration(srcDir, includeDrafts, includeFuture)
 	if err != nil {
 		return err
 	}
@@ -8,7 +8,7 @@ import (
 	"codeberg.org/asartalo/assg/internal/config"
 )
 
-func LoadServeConfiguration(srcDir string, includeDrafts bool) (*config.Config, error) {
+func LoadServeConfiguration(srcDir string, includeDrafts bool, includeFuture bool) (*config.Config, error) {
 	serveDirectory, err := os.MkdirTemp("", "public-assg")
 	if err != nil {
 		return nil, err
@@ -22,6 +22,9 @@ func LoadServeConfiguration(srcDir string, includeDrafts bool) (*config.Config,
 	conf.DevMode = true
 	conf.OutputDirectory = serveDirectory
 	conf.IncludeDrafts = includeDrafts
+	if includeFuture {
+		conf.IncludeFuture = true
+	}
 	conf.BaseURL = fmt.Sprintf("http://localhost:%d", conf.ServerConfig.Port)
 
 	return conf, nil
@@ -94,10 +94,12 @@ func (r *RecursiveWatcher) start() {
 					}
 				}
 
+				// new files and files saved by renaming, like data files
+				// written by editors, trigger a rebuild too
 				if event.Op&fsnotify.Remove == fsnotify.Remove {
 					r.watcher.Remove(event.Name)
 					debouncedCallback(event.Name)
-				} else if event.Op&fsnotify.Write == fsnotify.Write {
+				} else if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
 					debouncedCallback(event.Name)
 				}
 
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func watchForChange(t *testing.T, dir string, change func()) string {
+	changed := make(chan string, 1)
+	watcher, err := NewRecursiveWatcher(dir, []string{}, func(name string) {
+		select {
+		case changed <- name:
+		default:
+		}
+	})
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	change()
+
+	select {
+	case name := <-changed:
+		return name
+	case <-time.After(2 * time.Second):
+		t.Fatal("no rebuild after the change")
+		return ""
+	}
+}
+
+func TestWatcherRebuildsOnNewDataFile(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	assert.NoError(t, os.Mkdir(dataDir, 0755))
+	talks := filepath.Join(dataDir, "talks.toml")
+
+	name := watchForChange(t, dir, func() {
+		assert.NoError(t, os.WriteFile(talks, []byte("title = \"Go\"\n"), 0600))
+	})
+
+	assert.Equal(t, talks, name)
+}
+
+func TestWatcherRebuildsOnDataFileSavedByRenaming(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	assert.NoError(t, os.Mkdir(dataDir, 0755))
+	talks := filepath.Join(dataDir, "talks.toml")
+	assert.NoError(t, os.WriteFile(talks, []byte("title = \"Go\"\n"), 0600))
+	saved := filepath.Join(dir, "talks.toml.tmp")
+	assert.NoError(t, os.WriteFile(saved, []byte("title = \"Rust\"\n"), 0600))
+
+	name := watchForChange(t, dataDir, func() {
+		assert.NoError(t, os.Rename(saved, talks))
+	})
+
+	assert.Equal(t, talks, name)
+}
@@ -25,21 +25,39 @@ func New(funcMap template.FuncMap) *Engine {
 	return &Engine{funcMap: funcs(funcMap)}
 }
 
+// DefaultExcerptWords is the number of words taken from content without
+// paragraphs when no excerpt length is set.
+const DefaultExcerptWords = 30
+
+var paragraphRegex = regexp.MustCompile(`(?s)<p>(.+?)</p>`)
+
+var tagRegex = regexp.MustCompile(`<[^>]*>`)
+
 func FirstParagraphFromHtml(htmlContent template.HTML) template.HTML {
-	// Regular expression to find the first paragraph
-	paragraphRegex := regexp.MustCompile(`(?s)<p>(.+?)</p>`)
+	return ExcerptFromHtml(htmlContent, 0)
+}
 
+// ExcerptFromHtml returns the first paragraph of the content. If maxWords is
+// not 0 and the paragraph is longer, only the text of its first maxWords words
+// is kept.
+func ExcerptFromHtml(htmlContent template.HTML, maxWords int) template.HTML {
 	// Find the first paragraph match
 	match := paragraphRegex.FindStringSubmatch(string(htmlContent))
 
 	if len(match) > 1 {
-		// Paragraph found, return it
-		return template.HTML(match[0])
+		if maxWords == 0 || len(strings.Fields(tagRegex.ReplaceAllString(match[1], " "))) <= maxWords {
+			// Paragraph found, return it
+			return template.HTML(match[0])
+		}
+
+		htmlContent = template.HTML(tagRegex.ReplaceAllString(match[1], " "))
 	}
 
-	// No paragraph found, extract first 30 words
+	// No paragraph found, extract the first words
 	words := strings.Fields(strings.TrimSpace(string(htmlContent)))
-	maxWords := 30
+	if maxWords == 0 {
+		maxWords = DefaultExcerptWords
+	}
 
 	if len(words) > maxWords {
 		words = words[:maxWords]
@@ -58,9 +76,14 @@ func FirstParagraphFromString(content string) string {
 	return string(FirstParagraphFromHtml(template.HTML(content)))
 }
 
+func ExcerptFromString(content string, maxWords int) string {
+	return string(ExcerptFromHtml(template.HTML(content), maxWords))
+}
+
 func funcs(otherFuncMap template.FuncMap) template.FuncMap {
 	initMap := sprig.HtmlFuncMap()
 	initMap["firstParagraph"] = FirstParagraphFromHtml
+	initMap["excerpt"] = ExcerptFromHtml
 	initMap["timeAttr"] = func(time time.Time) string {
 		return time.Format("2006-01-02T15:04:05Z07:00")
 	}
@@ -176,6 +199,17 @@ func (e *Engine) RenderTemplate(name string, result io.Writer, data interface{})
 	return formathtml.Document(result, b)
 }
 
+// RenderPartial renders a template that produces an HTML fragment, such as a
+// shortcode, without formatting it as a document.
+func (e *Engine) RenderPartial(name string, result io.Writer, data interface{}) error {
+	tmpl, ok := e.Templates[name]
+	if !ok {
+		return fmt.Errorf("template %s not found", name)
+	}
+
+	return tmpl.ExecuteTemplate(result, name, data)
+}
+
 func (e *Engine) TemplateExists(name string) bool {
 	_, ok := e.Templates[name]
 	return ok
@@ -35,7 +35,7 @@ var buildCmd = &cobra.Command{
 		}
 
 		outputDir := filepath.Join(srcDir, "public")
-		err = commands.Build(srcDir, outputDir, false, verbose, time.Now())
+		err = commands.Build(srcDir, outputDir, false, includeFuture, verbose, time.Now())
 		if err != nil {
 			fmt.Println("Error:", err)
 			return
@@ -54,7 +54,27 @@ var serveCmd = &cobra.Command{
 			return
 		}
 
-		err = commands.Serve(srcDir, false, verbose)
+		err = commands.Serve(srcDir, includeDrafts, includeFuture, verbose)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	},
+}
+
+var highlightCssCmd = &cobra.Command{
+	Use:   "highlight-css",
+	Short: "Write the syntax highlighting stylesheet",
+	Long: "Writes the CSS for the configured highlight style. Use this when\n" +
+		"`highlight_classes` is enabled under the [markdown] section of config.toml.",
+	Run: func(cmd *cobra.Command, args []string) {
+		srcDir, err := os.Getwd()
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		err = commands.HighlightCSS(srcDir, highlightStyle, highlightOutput)
 		if err != nil {
 			fmt.Println("Error:", err)
 			return
@@ -63,16 +83,24 @@ var serveCmd = &cobra.Command{
 }
 
 var includeDrafts bool
+var includeFuture bool
 var verbose bool
+var highlightStyle string
+var highlightOutput string
 
 func init() {
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(highlightCssCmd)
 
 	// Add flags
 	buildCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print verbose output")
+	buildCmd.Flags().BoolVar(&includeFuture, "future", false, "Include pages dated in the future")
 	serveCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print verbose output")
 	serveCmd.Flags().BoolVar(&includeDrafts, "include-drafts", false, "Include draft pages when serving")
+	serveCmd.Flags().BoolVar(&includeFuture, "future", false, "Include pages dated in the future when serving")
+	highlightCssCmd.Flags().StringVarP(&highlightStyle, "style", "s", "", "Highlight style to use instead of the configured one")
+	highlightCssCmd.Flags().StringVarP(&highlightOutput, "output", "o", "", "Write the stylesheet to a file instead of stdout")
 }
 
 func main() {